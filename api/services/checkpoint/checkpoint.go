@@ -1,6 +1,9 @@
 package checkpoint
 
-import "github.com/YLonely/cer-manager/api/types"
+import (
+	"github.com/YLonely/cer-manager/api/services"
+	"github.com/YLonely/cer-manager/api/types"
+)
 
 const (
 	MethodGetCheckpoint string = "Get"
@@ -9,10 +12,14 @@ const (
 
 type GetCheckpointRequest struct {
 	Ref types.Reference `json:"ref"`
+	// Detach keeps the checkpoint in use after the connection is closed,
+	// or it is put back automatically
+	Detach bool `json:"detach,omitempty"`
 }
 
 type GetCheckpointResponse struct {
-	Path string `json:"path"`
+	Path  string          `json:"path"`
+	Error *services.Error `json:"error,omitempty"`
 }
 
 type PutCheckpointRequest struct {
@@ -20,5 +27,5 @@ type PutCheckpointRequest struct {
 }
 
 type PutCheckpointResponse struct {
-	Error string `json:"error,omitempty"`
+	Error *services.Error `json:"error,omitempty"`
 }
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Code classifies the errors returned by the services
+type Code string
+
+const (
+	CodeNotFound         Code = "NotFound"
+	CodeExhausted        Code = "Exhausted"
+	CodeInvalidArgument  Code = "InvalidArgument"
+	CodeInternal         Code = "Internal"
+	CodeUnimplemented    Code = "Unimplemented"
+	CodePermissionDenied Code = "PermissionDenied"
+)
+
+// Error is the error envelope carried by the responses of all the service methods
+type Error struct {
+	Code    Code   `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// Errors of each code, use errors.Is(err, ErrNotFound) to test the code of an error
+var (
+	ErrNotFound         = &Error{Code: CodeNotFound}
+	ErrExhausted        = &Error{Code: CodeExhausted}
+	ErrInvalidArgument  = &Error{Code: CodeInvalidArgument}
+	ErrInternal         = &Error{Code: CodeInternal}
+	ErrUnimplemented    = &Error{Code: CodeUnimplemented}
+	ErrPermissionDenied = &Error{Code: CodePermissionDenied}
+)
+
+// Errorf returns an error with code and formatted message
+func Errorf(code Code, format string, args ...interface{}) *Error {
+	return &Error{
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// ToError converts err to an error envelope, the code of err is kept if err wraps an *Error
+// or the code will be CodeInternal
+func ToError(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return &Error{Code: e.Code, Message: err.Error()}
+	}
+	return &Error{Code: CodeInternal, Message: err.Error()}
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return string(e.Code)
+	}
+	return e.Message
+}
+
+// Is reports whether target is an *Error with the same code
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
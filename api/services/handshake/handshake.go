@@ -0,0 +1,21 @@
+package handshake
+
+import "github.com/YLonely/cer-manager/api/services"
+
+const (
+	MethodHandshake string = "Handshake"
+)
+
+// HandshakeRequest is the first request sent by a client on a new connection
+type HandshakeRequest struct {
+	Version    uint16 `json:"version"`
+	MinVersion uint16 `json:"min_version"`
+}
+
+type HandshakeResponse struct {
+	// Version is the protocol version agreed by both sides
+	Version uint16 `json:"version"`
+	// Services maps the names of services supported by the daemon to their methods
+	Services map[string][]string `json:"services,omitempty"`
+	Error    *services.Error     `json:"error,omitempty"`
+}
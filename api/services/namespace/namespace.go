@@ -1,32 +1,53 @@
 package namespace
 
-import "github.com/YLonely/cer-manager/api/types"
+import (
+	"time"
+
+	"github.com/YLonely/cer-manager/api/services"
+	"github.com/YLonely/cer-manager/api/types"
+)
 
 const (
-	MethodGetNamespace    string = "Get"
-	MethodPutNamespace    string = "Put"
-	MethodUpdateNamespace string = "Update"
+	MethodGetNamespace     string = "Get"
+	MethodPutNamespace     string = "Put"
+	MethodUpdateNamespace  string = "Update"
+	MethodRenewNamespace   string = "Renew"
+	MethodInspectNamespace string = "Inspect"
+	MethodGetSandbox       string = "GetSandbox"
+	MethodPutSandbox       string = "PutSandbox"
 )
 
 type GetNamespaceRequest struct {
 	T         types.NamespaceType `json:"namespace_type"`
 	Ref       types.Reference     `json:"ref"`
 	ExtraRefs []types.Reference   `json:"extra_refs,omitempty"`
+	// Detach keeps the namespace checked out after the connection is closed,
+	// or it is put back automatically
+	Detach bool `json:"detach,omitempty"`
+	// LeaseTTL is the ttl of the lease on the namespace, the default ttl of the daemon is used if it is zero
+	LeaseTTL time.Duration `json:"lease_ttl,omitempty"`
+	// Wait is how long to wait for a namespace if all of them are used, Get fails at once if it is zero
+	Wait time.Duration `json:"wait,omitempty"`
 }
 
 type PutNamespaceRequest struct {
-	T  types.NamespaceType `json:"namespace_type"`
-	ID int                 `json:"namespace_id"`
+	T types.NamespaceType `json:"namespace_type"`
+	// ID is the handle returned by Get
+	ID string `json:"namespace_id"`
 }
 
 type PutNamespaceResponse struct {
-	Error string `json:"error,omitempty"`
+	Error *services.Error `json:"error,omitempty"`
 }
 
+// GetNamespaceResponse is sent along with the namespace file as ancillary data,
+// ID is an opaque handle used to put the namespace back, it is never reused
 type GetNamespaceResponse struct {
-	Pid  int         `json:"pid"`
-	Fd   int         `json:"namespace_fd"`
+	ID   string      `json:"namespace_id"`
 	Info interface{} `json:"info,omitempty"`
+	// Lease is nil if neither the request nor the daemon sets a lease ttl
+	Lease *types.Lease    `json:"lease,omitempty"`
+	Error *services.Error `json:"error,omitempty"`
 }
 
 type UpdateNamespaceRequest struct {
@@ -35,5 +56,72 @@ type UpdateNamespaceRequest struct {
 }
 
 type UpdateNamespaceResponse struct {
-	Error string `json:"error,omitempty"`
+	Error *services.Error `json:"error,omitempty"`
+}
+
+type RenewNamespaceRequest struct {
+	T       types.NamespaceType `json:"namespace_type"`
+	LeaseID string              `json:"lease_id"`
+	// TTL is the new ttl of the lease, the ttl of the lease is kept if it is zero
+	TTL time.Duration `json:"ttl,omitempty"`
+}
+
+type RenewNamespaceResponse struct {
+	Lease types.Lease     `json:"lease"`
+	Error *services.Error `json:"error,omitempty"`
+}
+
+// InspectNamespaceRequest inspects the managers of T, or all the managers if T is empty
+type InspectNamespaceRequest struct {
+	T types.NamespaceType `json:"namespace_type,omitempty"`
+}
+
+type InspectNamespaceResponse struct {
+	Leases []types.Lease `json:"leases"`
+	// Refill is the state of the workers refilling the namespaces of all types
+	Refill *types.RefillStatus `json:"refill,omitempty"`
+	// Budget is the usage of the resources held by the namespaces of all types
+	Budget *types.BudgetStatus `json:"budget,omitempty"`
+	// Health are the results of the health checks of the idle namespaces of each reference
+	Health []types.HealthStatus `json:"health,omitempty"`
+	Error  *services.Error      `json:"error,omitempty"`
+}
+
+// GetSandboxRequest checks out the namespaces of Types and the checkpoint of Ref all together
+type GetSandboxRequest struct {
+	Ref types.Reference `json:"ref"`
+	// Types are the namespace types of the sandbox, ipc, uts and mnt are used if it is empty
+	Types []types.NamespaceType `json:"namespace_types,omitempty"`
+	// Detach keeps the sandbox checked out after the connection is closed
+	Detach bool `json:"detach,omitempty"`
+	// LeaseTTL is the ttl of the lease on each namespace, the default ttl of the daemon is used if it is zero.
+	// The whole sandbox is put back once any of the leases expires
+	LeaseTTL time.Duration `json:"lease_ttl,omitempty"`
+	// Wait is the deadline for all the namespaces to become available, GetSandbox fails at once if it is zero
+	Wait time.Duration `json:"wait,omitempty"`
+}
+
+// SandboxNamespace is a namespace in a sandbox
+type SandboxNamespace struct {
+	T     types.NamespaceType `json:"namespace_type"`
+	ID    string              `json:"namespace_id"`
+	Info  interface{}         `json:"info,omitempty"`
+	Lease *types.Lease        `json:"lease,omitempty"`
+}
+
+// GetSandboxResponse is sent along with the namespace files as ancillary data,
+// the files are in the same order as Namespaces
+type GetSandboxResponse struct {
+	ID             string             `json:"sandbox_id"`
+	Namespaces     []SandboxNamespace `json:"namespaces"`
+	CheckpointPath string             `json:"checkpoint_path"`
+	Error          *services.Error    `json:"error,omitempty"`
+}
+
+type PutSandboxRequest struct {
+	ID string `json:"sandbox_id"`
+}
+
+type PutSandboxResponse struct {
+	Error *services.Error `json:"error,omitempty"`
 }
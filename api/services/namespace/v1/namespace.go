@@ -0,0 +1,37 @@
+package namespace
+
+import (
+	"time"
+
+	"github.com/YLonely/cer-manager/api/types"
+	typesv1 "github.com/YLonely/cer-manager/api/types/v1"
+)
+
+//go:generate make -C ../../../.. protos
+
+// FromLease converts a lease to its protobuf message
+func FromLease(l types.Lease) *Lease {
+	return &Lease{
+		ID:            l.ID,
+		NamespaceType: string(l.NamespaceType),
+		NamespaceID:   l.NamespaceID,
+		Ref:           typesv1.FromReference(l.Ref),
+		TTL:           int64(l.TTL),
+		Expire:        l.Expire.UnixNano(),
+	}
+}
+
+// ToLease converts the message back to a lease
+func (m *Lease) ToLease() types.Lease {
+	if m == nil {
+		return types.Lease{}
+	}
+	return types.Lease{
+		ID:            m.ID,
+		NamespaceType: types.NamespaceType(m.NamespaceType),
+		NamespaceID:   m.NamespaceID,
+		Ref:           m.Ref.ToReference(),
+		TTL:           time.Duration(m.TTL),
+		Expire:        time.Unix(0, m.Expire),
+	}
+}
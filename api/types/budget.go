@@ -0,0 +1,35 @@
+package types
+
+// Resources are the host resources held by the namespaces
+type Resources struct {
+	Namespaces int `json:"namespaces,omitempty"`
+	// UpperBytes is the disk usage of the overlay upper dirs of the mount namespaces
+	UpperBytes int64 `json:"upper_bytes,omitempty"`
+	// ShmBytes is the size of the SysV shared memory restored in the ipc namespaces
+	ShmBytes int64 `json:"shm_bytes,omitempty"`
+}
+
+// Add returns the sum of r and o
+func (r Resources) Add(o Resources) Resources {
+	return Resources{
+		Namespaces: r.Namespaces + o.Namespaces,
+		UpperBytes: r.UpperBytes + o.UpperBytes,
+		ShmBytes:   r.ShmBytes + o.ShmBytes,
+	}
+}
+
+// Sub returns r minus o
+func (r Resources) Sub(o Resources) Resources {
+	return Resources{
+		Namespaces: r.Namespaces - o.Namespaces,
+		UpperBytes: r.UpperBytes - o.UpperBytes,
+		ShmBytes:   r.ShmBytes - o.ShmBytes,
+	}
+}
+
+// BudgetStatus is the usage of the resources held by all the namespaces against their limits,
+// a zero limit means unlimited
+type BudgetStatus struct {
+	Limits Resources `json:"limits"`
+	Used   Resources `json:"used"`
+}
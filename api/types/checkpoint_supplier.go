@@ -4,3 +4,11 @@ package types
 type Supplier interface {
 	Get(ref Reference) (string, error)
 }
+
+// SharedSupplier is a Supplier which counts the users of each checkpoint,
+// a checkpoint acquired must be released after use
+type SharedSupplier interface {
+	Supplier
+	Acquire(ref Reference) (string, error)
+	Release(ref Reference)
+}
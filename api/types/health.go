@@ -0,0 +1,18 @@
+package types
+
+import "time"
+
+// HealthStatus is the result of the health checks of the idle namespaces of a type of a reference
+type HealthStatus struct {
+	NamespaceType NamespaceType `json:"namespace_type"`
+	Ref           Reference     `json:"ref"`
+	// Checked is the number of namespaces checked in the last round
+	Checked int `json:"checked"`
+	// Unhealthy is the number of namespaces found unhealthy in the last round
+	Unhealthy int `json:"unhealthy"`
+	// Evicted is the number of unhealthy namespaces evicted and replaced since the start
+	Evicted   int       `json:"evicted"`
+	LastCheck time.Time `json:"last_check,omitempty"`
+	// LastError is the reason the last unhealthy namespace is evicted
+	LastError string `json:"last_error,omitempty"`
+}
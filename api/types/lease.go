@@ -0,0 +1,17 @@
+package types
+
+import "time"
+
+// Lease bounds the time a namespace can be checked out, the namespace is put back once the lease expires
+type Lease struct {
+	ID            string        `json:"id"`
+	NamespaceType NamespaceType `json:"namespace_type"`
+	NamespaceID   string        `json:"namespace_id"`
+	Ref           Reference     `json:"ref"`
+	TTL           time.Duration `json:"ttl"`
+	Expire        time.Time     `json:"expire"`
+}
+
+func (l Lease) Expired(now time.Time) bool {
+	return !now.Before(l.Expire)
+}
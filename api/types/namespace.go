@@ -3,7 +3,11 @@ package types
 type NamespaceType string
 
 const (
-	NamespaceIPC NamespaceType = "ipc"
-	NamespaceUTS NamespaceType = "uts"
-	NamespaceMNT NamespaceType = "mnt"
+	NamespaceIPC    NamespaceType = "ipc"
+	NamespaceUTS    NamespaceType = "uts"
+	NamespaceMNT    NamespaceType = "mnt"
+	NamespaceNET    NamespaceType = "net"
+	NamespaceCGROUP NamespaceType = "cgroup"
+	NamespaceUSER   NamespaceType = "user"
+	NamespaceTIME   NamespaceType = "time"
 )
@@ -0,0 +1,12 @@
+package types
+
+// RefillStatus is the state of the workers refilling the namespace sets
+type RefillStatus struct {
+	Workers int `json:"workers"`
+	// Running is the number of namespaces being created
+	Running int `json:"running"`
+	// Queued is the number of sets waiting for a worker
+	Queued int `json:"queued"`
+	// BackingOff is the number of sets waiting to retry after failing to create namespaces
+	BackingOff int `json:"backing_off"`
+}
@@ -0,0 +1,11 @@
+package types
+
+// Selection is the info of a namespace got for several references
+type Selection struct {
+	// Ref is the reference the namespace is picked from
+	Ref Reference `json:"ref"`
+	// Reason tells why the namespace of Ref is picked
+	Reason string `json:"reason"`
+	// Info is the info of the namespace as if it is got for Ref alone
+	Info interface{} `json:"info,omitempty"`
+}
@@ -0,0 +1,27 @@
+package types
+
+import (
+	"github.com/YLonely/cer-manager/api/services"
+)
+
+// FromError converts the error envelope to its protobuf message
+func FromError(e *services.Error) *Error {
+	if e == nil {
+		return nil
+	}
+	return &Error{
+		Code:    string(e.Code),
+		Message: e.Message,
+	}
+}
+
+// ToError converts the message back to an error envelope, nil is returned if there is no error
+func (m *Error) ToError() error {
+	if m == nil {
+		return nil
+	}
+	return &services.Error{
+		Code:    services.Code(m.Code),
+		Message: m.Message,
+	}
+}
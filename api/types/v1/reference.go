@@ -0,0 +1,42 @@
+package types
+
+import (
+	"github.com/YLonely/cer-manager/api/types"
+)
+
+// FromReference converts ref to its protobuf message
+func FromReference(ref types.Reference) *Reference {
+	return &Reference{
+		Name:   ref.Name,
+		Labels: ref.Labels,
+	}
+}
+
+// FromReferences converts refs to their protobuf messages
+func FromReferences(refs []types.Reference) []*Reference {
+	ret := make([]*Reference, 0, len(refs))
+	for _, ref := range refs {
+		ret = append(ret, FromReference(ref))
+	}
+	return ret
+}
+
+// ToReference converts the message to a types.Reference
+func (m *Reference) ToReference() types.Reference {
+	if m == nil {
+		return types.Reference{}
+	}
+	return types.Reference{
+		Name:   m.Name,
+		Labels: m.Labels,
+	}
+}
+
+// ToReferences converts the messages to types.Reference
+func ToReferences(ms []*Reference) []types.Reference {
+	ret := make([]types.Reference, 0, len(ms))
+	for _, m := range ms {
+		ret = append(ret, m.ToReference())
+	}
+	return ret
+}
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"net"
+
+	"github.com/containerd/ttrpc"
+	"github.com/pkg/errors"
+)
+
+type peerKey struct{}
+
+type peer struct {
+	cred   Credential
+	policy *Policy
+}
+
+// WithPeer returns a context carrying the credential of the peer of a connection and the policy to check it against
+func WithPeer(ctx context.Context, cred Credential, policy *Policy) context.Context {
+	return context.WithValue(ctx, peerKey{}, peer{cred: cred, policy: policy})
+}
+
+// PeerFromContext returns the credential carried by ctx
+func PeerFromContext(ctx context.Context) (Credential, bool) {
+	p, ok := ctx.Value(peerKey{}).(peer)
+	return p.cred, ok
+}
+
+// Authorize checks the request against the peer carried by ctx,
+// the requests from a context without a peer have been authorized by the transport
+func Authorize(ctx context.Context, r Request) error {
+	p, ok := ctx.Value(peerKey{}).(peer)
+	if !ok {
+		return nil
+	}
+	return p.policy.Authorize(p.cred, r)
+}
+
+// Unrestricted reports whether the peer carried by ctx is allowed to make any request,
+// the requests from a context without a peer have been authorized by the transport
+func Unrestricted(ctx context.Context) bool {
+	p, ok := ctx.Value(peerKey{}).(peer)
+	return !ok || p.policy.Unrestricted(p.cred)
+}
+
+// TTRPCHandshaker returns a handshaker which only accepts the unrestricted peers,
+// as ttrpc does not pass the credential to the methods, the others have to use the daemon socket
+func (p *Policy) TTRPCHandshaker() ttrpc.Handshaker {
+	return handshaker(func(ctx context.Context, conn net.Conn) (net.Conn, interface{}, error) {
+		cred, err := PeerCredential(conn)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !p.Unrestricted(cred) {
+			return nil, nil, errors.Errorf("peer(%s) is not allowed to use the ttrpc socket", cred)
+		}
+		return conn, cred, nil
+	})
+}
+
+type handshaker func(ctx context.Context, conn net.Conn) (net.Conn, interface{}, error)
+
+func (fn handshaker) Handshake(ctx context.Context, conn net.Conn) (net.Conn, interface{}, error) {
+	return fn(ctx, conn)
+}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// Credential identifies the process on the other side of a unix socket
+type Credential struct {
+	PID int `json:"pid"`
+	UID int `json:"uid"`
+	GID int `json:"gid"`
+	// Cgroups contains the cgroup path of the process in each hierarchy
+	Cgroups []string `json:"cgroups,omitempty"`
+}
+
+// PeerCredential reads the credential of the peer of conn with SO_PEERCRED
+func PeerCredential(conn net.Conn) (Credential, error) {
+	uc, ok := conn.(*net.UnixConn)
+	if !ok {
+		return Credential{}, errors.Errorf("connection of type %T is not a unix connection", conn)
+	}
+	raw, err := uc.SyscallConn()
+	if err != nil {
+		return Credential{}, err
+	}
+	var (
+		ucred    *unix.Ucred
+		ucredErr error
+	)
+	if err = raw.Control(func(fd uintptr) {
+		ucred, ucredErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	}); err != nil {
+		return Credential{}, err
+	}
+	if ucredErr != nil {
+		return Credential{}, errors.Wrap(ucredErr, "failed to get the peer credential")
+	}
+	cred := Credential{
+		PID: int(ucred.Pid),
+		UID: int(ucred.Uid),
+		GID: int(ucred.Gid),
+	}
+	// the peer may exit at any time, a credential without cgroups only matches the rules without cgroups
+	cred.Cgroups, _ = readCgroups(cred.PID)
+	return cred, nil
+}
+
+func (c Credential) String() string {
+	return fmt.Sprintf("pid=%d,uid=%d,gid=%d", c.PID, c.UID, c.GID)
+}
+
+// readCgroups returns the cgroup paths of process pid in /proc/<pid>/cgroup
+func readCgroups(pid int) ([]string, error) {
+	f, err := os.Open(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var ret []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// hierarchy-ID:controller-list:cgroup-path
+		parts := strings.SplitN(scanner.Text(), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		ret = append(ret, parts[2])
+	}
+	return ret, scanner.Err()
+}
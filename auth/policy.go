@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	cerm "github.com/YLonely/cer-manager"
+	apiservices "github.com/YLonely/cer-manager/api/services"
+	"github.com/YLonely/cer-manager/api/types"
+	"github.com/pkg/errors"
+)
+
+// Policy decides which peer may call which method, a call is allowed if any of the rules allows it
+type Policy struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Rule allows the peers matching UIDs, GIDs and Cgroups to call the Methods of Services,
+// on the References and NamespaceTypes. An empty field matches anything.
+type Rule struct {
+	UIDs []int `json:"uids,omitempty"`
+	GIDs []int `json:"gids,omitempty"`
+	// Cgroups contains the prefixes of the cgroup path of the peer
+	Cgroups  []string `json:"cgroups,omitempty"`
+	Services []string `json:"services,omitempty"`
+	Methods  []string `json:"methods,omitempty"`
+	// References matches the refs carried by the requests, the name and all the labels of a reference must be equal.
+	// Put and Renew of the namespace service are matched on the refs the namespace is got for, and are allowed
+	// only to the connection or the user holding the namespace unless the peer is unrestricted.
+	References     []types.Reference     `json:"references,omitempty"`
+	NamespaceTypes []types.NamespaceType `json:"namespace_types,omitempty"`
+}
+
+// Request describes a call to be authorized
+type Request struct {
+	Service        cerm.ServiceType
+	Method         string
+	Refs           []types.Reference
+	NamespaceTypes []types.NamespaceType
+}
+
+// LoadPolicy reads the policy from a json file
+func LoadPolicy(path string) (*Policy, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read policy file")
+	}
+	p := &Policy{}
+	if err = json.Unmarshal(content, p); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal policy file")
+	}
+	for _, rule := range p.Rules {
+		for _, svr := range rule.Services {
+			if !validService(svr) {
+				return nil, errors.Errorf("unknown service %s in policy file", svr)
+			}
+		}
+	}
+	return p, nil
+}
+
+// Authorize returns an error with code CodePermissionDenied if cred is not allowed to make the request.
+// A nil policy allows everything and the daemon itself is always allowed.
+func (p *Policy) Authorize(cred Credential, r Request) error {
+	if p == nil || cred.PID == os.Getpid() {
+		return nil
+	}
+	for _, rule := range p.Rules {
+		if rule.matchPeer(cred) && rule.matchRequest(r) {
+			return nil
+		}
+	}
+	return apiservices.Errorf(
+		apiservices.CodePermissionDenied,
+		"peer(%s) is not allowed to call %s.%s", cred, cerm.Type2Services[r.Service], r.Method,
+	)
+}
+
+// Unrestricted reports whether cred is allowed to make any request
+func (p *Policy) Unrestricted(cred Credential) bool {
+	if p == nil || cred.PID == os.Getpid() {
+		return true
+	}
+	for _, rule := range p.Rules {
+		if rule.matchPeer(cred) && len(rule.Services) == 0 && len(rule.Methods) == 0 &&
+			len(rule.References) == 0 && len(rule.NamespaceTypes) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (rule Rule) matchPeer(cred Credential) bool {
+	if len(rule.UIDs) != 0 && !containsInt(rule.UIDs, cred.UID) {
+		return false
+	}
+	if len(rule.GIDs) != 0 && !containsInt(rule.GIDs, cred.GID) {
+		return false
+	}
+	if len(rule.Cgroups) == 0 {
+		return true
+	}
+	for _, prefix := range rule.Cgroups {
+		for _, cgroup := range cred.Cgroups {
+			if cgroupHasPrefix(cgroup, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (rule Rule) matchRequest(r Request) bool {
+	if len(rule.Services) != 0 && !containsString(rule.Services, cerm.Type2Services[r.Service]) {
+		return false
+	}
+	if len(rule.Methods) != 0 && !containsString(rule.Methods, r.Method) {
+		return false
+	}
+	if len(rule.References) != 0 {
+		for _, ref := range r.Refs {
+			if !rule.matchReference(ref) {
+				return false
+			}
+		}
+	}
+	if len(rule.NamespaceTypes) != 0 {
+		for _, t := range r.NamespaceTypes {
+			if !containsNamespaceType(rule.NamespaceTypes, t) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (rule Rule) matchReference(ref types.Reference) bool {
+	for _, allowed := range rule.References {
+		if allowed.Name != ref.Name {
+			continue
+		}
+		matched := true
+		for k, v := range allowed.Labels {
+			if ref.Labels[k] != v {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func cgroupHasPrefix(cgroup, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return cgroup == prefix || strings.HasPrefix(cgroup, prefix+"/") || prefix == ""
+}
+
+func validService(name string) bool {
+	for _, svr := range cerm.Type2Services {
+		if svr == name {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(s []int, v int) bool {
+	for _, i := range s {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(s []string, v string) bool {
+	for _, i := range s {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsNamespaceType(s []types.NamespaceType, v types.NamespaceType) bool {
+	for _, i := range s {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
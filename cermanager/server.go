@@ -5,44 +5,77 @@ import (
 	"io"
 	"net"
 	"os"
+	"os/user"
 	"path"
+	"strconv"
 	"sync"
 
 	cerm "github.com/YLonely/cer-manager"
+	apiservices "github.com/YLonely/cer-manager/api/services"
+	"github.com/YLonely/cer-manager/api/services/handshake"
 	"github.com/YLonely/cer-manager/api/types"
+	"github.com/YLonely/cer-manager/auth"
 	"github.com/YLonely/cer-manager/http"
 	"github.com/YLonely/cer-manager/log"
 	"github.com/YLonely/cer-manager/services"
 	"github.com/YLonely/cer-manager/services/checkpoint"
 	"github.com/YLonely/cer-manager/services/namespace"
 	"github.com/YLonely/cer-manager/utils"
+	"github.com/containerd/ttrpc"
 	"github.com/pkg/errors"
 )
 
 const DefaultRootPath = "/var/lib/cermanager"
 const DefaultSocketName = "daemon.socket"
+const DefaultTTRPCSocketName = "daemon.ttrpc.socket"
+
+// Config configures the sockets and the access control of the server
+type Config struct {
+	// HTTPPort enables the http server if it is not zero
+	HTTPPort int
+	// SocketMode is the file mode of the sockets, the mode is left as it is if SocketMode is zero
+	SocketMode os.FileMode
+	// SocketGroup is the name or the id of the group owning the sockets
+	SocketGroup string
+	// PolicyPath is the path of the access policy file, all the peers are allowed if it is empty
+	PolicyPath string
+}
 
 type Server struct {
-	services   map[cerm.ServiceType]services.Service
-	httpServer *http.Server
-	listener   net.Listener
-	group      sync.WaitGroup
+	services      map[cerm.ServiceType]services.Service
+	policy        *auth.Policy
+	httpServer    *http.Server
+	listener      net.Listener
+	ttrpcServer   *ttrpc.Server
+	ttrpcListener net.Listener
+	group         sync.WaitGroup
+	// lastConn is the id of the last accepted connection
+	lastConn uint64
 }
 
-func NewServer(httpPort int) (*Server, error) {
+func NewServer(config Config) (*Server, error) {
 	if err := os.MkdirAll(DefaultRootPath, 0755); err != nil {
 		return nil, err
 	}
-	socketPath := path.Join(DefaultRootPath, DefaultSocketName)
-	os.Remove(socketPath)
-	addr, err := net.ResolveUnixAddr("unix", socketPath)
+	var policy *auth.Policy
+	if config.PolicyPath != "" {
+		var err error
+		if policy, err = auth.LoadPolicy(config.PolicyPath); err != nil {
+			return nil, err
+		}
+	}
+	listener, err := listenUnix(path.Join(DefaultRootPath, DefaultSocketName), config)
 	if err != nil {
 		return nil, err
 	}
-	listener, err := net.ListenUnix("unix", addr)
+	ttrpcListener, err := listenUnix(path.Join(DefaultRootPath, DefaultTTRPCSocketName), config)
 	if err != nil {
 		return nil, err
 	}
+	ttrpcServer, err := ttrpc.NewServer(ttrpc.WithServerHandshaker(policy.TTRPCHandshaker()))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create ttrpc server")
+	}
 	checkpointSvr, err := checkpoint.New(DefaultRootPath)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create checkpoint service")
@@ -52,8 +85,8 @@ func NewServer(httpPort int) (*Server, error) {
 		return nil, errors.Wrap(err, "failed to create namespace service")
 	}
 	var httpServer *http.Server
-	if httpPort != 0 {
-		httpServer, err = http.NewServer(DefaultRootPath, httpPort)
+	if config.HTTPPort != 0 {
+		httpServer, err = http.NewServer(DefaultRootPath, config.HTTPPort)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to create http server")
 		}
@@ -63,17 +96,68 @@ func NewServer(httpPort int) (*Server, error) {
 			cerm.NamespaceService:  namespaceSvr,
 			cerm.CheckpointService: checkpointSvr,
 		},
-		listener:   listener,
-		httpServer: httpServer,
+		policy:        policy,
+		listener:      listener,
+		httpServer:    httpServer,
+		ttrpcServer:   ttrpcServer,
+		ttrpcListener: ttrpcListener,
 	}
 	for _, service := range svr.services {
 		if err = service.Init(); err != nil {
 			return nil, err
 		}
+		// both transports dispatch to the same service
+		if ts, ok := service.(services.TTRPCService); ok {
+			if err = ts.RegisterTTRPC(ttrpcServer); err != nil {
+				return nil, err
+			}
+		}
 	}
 	return svr, nil
 }
 
+func listenUnix(socketPath string, config Config) (net.Listener, error) {
+	os.Remove(socketPath)
+	addr, err := net.ResolveUnixAddr("unix", socketPath)
+	if err != nil {
+		return nil, err
+	}
+	l, err := net.ListenUnix("unix", addr)
+	if err != nil {
+		return nil, err
+	}
+	if config.SocketMode != 0 {
+		if err = os.Chmod(socketPath, config.SocketMode); err != nil {
+			l.Close()
+			return nil, errors.Wrapf(err, "failed to change the mode of %s", socketPath)
+		}
+	}
+	if config.SocketGroup != "" {
+		gid, err := lookupGroup(config.SocketGroup)
+		if err != nil {
+			l.Close()
+			return nil, err
+		}
+		if err = os.Chown(socketPath, -1, gid); err != nil {
+			l.Close()
+			return nil, errors.Wrapf(err, "failed to change the group of %s", socketPath)
+		}
+	}
+	return l, nil
+}
+
+// lookupGroup returns the id of group which is either a group name or a group id
+func lookupGroup(group string) (int, error) {
+	if gid, err := strconv.Atoi(group); err == nil {
+		return gid, nil
+	}
+	g, err := user.LookupGroup(group)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to lookup group %s", group)
+	}
+	return strconv.Atoi(g.Gid)
+}
+
 func (s *Server) Start(ctx context.Context) chan error {
 	errorC := make(chan error, 1)
 	if s.httpServer != nil {
@@ -83,6 +167,11 @@ func (s *Server) Start(ctx context.Context) chan error {
 			errorC <- err
 		}()
 	}
+	go func() {
+		if err := s.ttrpcServer.Serve(ctx, s.ttrpcListener); err != nil && err != ttrpc.ErrServerClosed {
+			errorC <- err
+		}
+	}()
 	go func() {
 		for {
 			conn, err := s.listener.Accept()
@@ -91,7 +180,8 @@ func (s *Server) Start(ctx context.Context) chan error {
 				return
 			}
 			s.group.Add(1)
-			go s.serve(ctx, conn, errorC)
+			s.lastConn++
+			go s.serve(services.WithConnection(ctx, s.lastConn), conn, errorC)
 			select {
 			case <-ctx.Done():
 				return
@@ -104,6 +194,19 @@ func (s *Server) Start(ctx context.Context) chan error {
 
 func (s *Server) serve(ctx context.Context, conn net.Conn, errorC chan error) {
 	defer s.group.Done()
+	defer s.reclaim(ctx)
+	cred, err := auth.PeerCredential(conn)
+	if err != nil {
+		log.Raw().WithError(err).Error("failed to get the peer credential")
+		conn.Close()
+		return
+	}
+	ctx = auth.WithPeer(ctx, cred, s.policy)
+	if err := s.handshake(conn); err != nil {
+		log.Logger(cerm.HandshakeService, handshake.MethodHandshake).WithError(err).Error("handshake failed")
+		conn.Close()
+		return
+	}
 	for {
 		svrType, err := utils.ReceiveServiceType(conn)
 		if err != nil {
@@ -127,7 +230,73 @@ func (s *Server) serve(ctx context.Context, conn net.Conn, errorC chan error) {
 	}
 }
 
+// reclaim takes back the resources still held by the closed connection
+func (s *Server) reclaim(ctx context.Context) {
+	conn, _ := services.ConnectionFromContext(ctx)
+	for _, svr := range s.services {
+		if r, ok := svr.(services.Reclaimer); ok {
+			r.Reclaim(conn)
+		}
+	}
+}
+
+// handshake negotiates the protocol version with the client and tells it which services and methods are supported
+func (s *Server) handshake(conn net.Conn) error {
+	svrType, err := utils.ReceiveServiceType(conn)
+	if err != nil {
+		return err
+	}
+	if svrType != cerm.HandshakeService {
+		return errors.Errorf("expect a handshake but receive a request of service type %v, the client may be too old", svrType)
+	}
+	var method string
+	if err = utils.ReceiveObject(conn, &method); err != nil {
+		return err
+	}
+	if method != handshake.MethodHandshake {
+		return errors.Errorf("invalid handshake method %s", method)
+	}
+	var r handshake.HandshakeRequest
+	if err = utils.ReceiveObject(conn, &r); err != nil {
+		return err
+	}
+	log.WithInterface(log.Logger(cerm.HandshakeService, handshake.MethodHandshake), "request", r).Debug()
+	rsp := handshake.HandshakeResponse{
+		Version:  cerm.ProtocolVersion,
+		Services: map[string][]string{},
+	}
+	if r.Version < rsp.Version {
+		rsp.Version = r.Version
+	}
+	minVersion := cerm.MinProtocolVersion
+	if r.MinVersion > minVersion {
+		minVersion = r.MinVersion
+	}
+	if rsp.Version < minVersion {
+		rsp.Error = apiservices.Errorf(
+			apiservices.CodeInvalidArgument,
+			"protocol version mismatch, daemon supports [%d,%d] while client supports [%d,%d]",
+			cerm.MinProtocolVersion, cerm.ProtocolVersion, r.MinVersion, r.Version,
+		)
+	} else {
+		for t, svr := range s.services {
+			rsp.Services[cerm.Type2Services[t]] = svr.Methods()
+		}
+	}
+	if err = utils.SendObject(conn, rsp); err != nil {
+		return err
+	}
+	log.WithInterface(log.Logger(cerm.HandshakeService, handshake.MethodHandshake), "response", rsp).Debug()
+	if rsp.Error != nil {
+		return rsp.Error
+	}
+	return nil
+}
+
 func (s *Server) Shutdown() {
+	if err := s.ttrpcServer.Close(); err != nil {
+		log.Raw().WithError(err).Error("ttrpc server shutdown with error")
+	}
 	s.group.Wait()
 	for t, ss := range s.services {
 		if err := ss.Stop(); err != nil {
@@ -7,22 +7,34 @@ import (
 	"github.com/YLonely/cer-manager/utils"
 )
 
-// GetCheckpoint returns the dir path in which checkpoint files of the container with ref located
+// GetCheckpoint returns the dir path in which checkpoint files of the container with ref located,
+// the checkpoint is put back automatically if the client is closed before PutCheckpoint
 func (client *Client) GetCheckpoint(ref types.Reference) (string, error) {
-	req := checkpoint.GetCheckpointRequest{
+	return client.getCheckpoint(checkpoint.GetCheckpointRequest{
 		Ref: ref,
-	}
-	data, err := utils.Pack(cermanager.CheckpointService, checkpoint.MethodGetCheckpoint, req)
+	})
+}
+
+// GetCheckpointDetached is the same as GetCheckpoint but the checkpoint stays in use after the client is closed
+func (client *Client) GetCheckpointDetached(ref types.Reference) (string, error) {
+	return client.getCheckpoint(checkpoint.GetCheckpointRequest{
+		Ref:    ref,
+		Detach: true,
+	})
+}
+
+func (client *Client) getCheckpoint(req checkpoint.GetCheckpointRequest) (string, error) {
+	err := client.send(cermanager.CheckpointService, checkpoint.MethodGetCheckpoint, req)
 	if err != nil {
 		return "", err
 	}
-	if err = utils.Send(client.c, data); err != nil {
-		return "", err
-	}
 	rsp := checkpoint.GetCheckpointResponse{}
 	if err = utils.ReceiveObject(client.c, &rsp); err != nil {
 		return "", err
 	}
+	if rsp.Error != nil {
+		return "", rsp.Error
+	}
 	return rsp.Path, nil
 }
 
@@ -31,16 +43,16 @@ func (client *Client) PutCheckpoint(ref types.Reference) error {
 	req := checkpoint.PutCheckpointRequest{
 		Ref: ref,
 	}
-	data, err := utils.Pack(cermanager.CheckpointService, checkpoint.MethodPutCheckpoint, req)
+	err := client.send(cermanager.CheckpointService, checkpoint.MethodPutCheckpoint, req)
 	if err != nil {
 		return err
 	}
-	if err = utils.Send(client.c, data); err != nil {
-		return err
-	}
 	rsp := checkpoint.PutCheckpointResponse{}
 	if err = utils.ReceiveObject(client.c, &rsp); err != nil {
 		return err
 	}
+	if rsp.Error != nil {
+		return rsp.Error
+	}
 	return nil
 }
@@ -2,6 +2,13 @@ package client
 
 import (
 	"net"
+	"os"
+
+	cerm "github.com/YLonely/cer-manager"
+	"github.com/YLonely/cer-manager/api/services"
+	"github.com/YLonely/cer-manager/api/services/handshake"
+	"github.com/YLonely/cer-manager/utils"
+	"github.com/pkg/errors"
 )
 
 const (
@@ -14,9 +21,14 @@ func New(config Config) (*Client, error) {
 	if c, err = net.Dial("unix", config.SocketPath); err != nil {
 		return nil, err
 	}
-	return &Client{
+	client := &Client{
 		c: c,
-	}, nil
+	}
+	if err = client.handshake(); err != nil {
+		c.Close()
+		return nil, errors.Wrap(err, "failed to handshake with cer-manager")
+	}
+	return client, nil
 }
 
 func Default() (*Client, error) {
@@ -31,8 +43,70 @@ type Config struct {
 
 type Client struct {
 	c net.Conn
+	// version is the protocol version agreed with the daemon
+	version uint16
+	// services maps the services supported by the daemon to their methods
+	services map[string]map[string]struct{}
 }
 
 func (client *Client) Close() error {
 	return client.c.Close()
 }
+
+// Version returns the protocol version agreed with the daemon
+func (client *Client) Version() uint16 {
+	return client.version
+}
+
+func (client *Client) handshake() error {
+	req := handshake.HandshakeRequest{
+		Version:    cerm.ProtocolVersion,
+		MinVersion: cerm.MinProtocolVersion,
+	}
+	data, err := utils.Pack(cerm.HandshakeService, handshake.MethodHandshake, req)
+	if err != nil {
+		return err
+	}
+	if err = utils.Send(client.c, data); err != nil {
+		return err
+	}
+	rsp := handshake.HandshakeResponse{}
+	if err = utils.ReceiveObject(client.c, &rsp); err != nil {
+		return errors.Wrap(err, "no valid handshake response, the daemon may be too old")
+	}
+	if rsp.Error != nil {
+		return rsp.Error
+	}
+	client.version = rsp.Version
+	client.services = map[string]map[string]struct{}{}
+	for name, methods := range rsp.Services {
+		client.services[name] = map[string]struct{}{}
+		for _, method := range methods {
+			client.services[name][method] = struct{}{}
+		}
+	}
+	return nil
+}
+
+// send packs the request and sends it to the daemon if the daemon supports the method
+func (client *Client) send(st cerm.ServiceType, method string, req interface{}) error {
+	name := cerm.Type2Services[st]
+	methods, exists := client.services[name]
+	if !exists {
+		return services.Errorf(services.CodeUnimplemented, "service %s is not supported by the daemon", name)
+	}
+	if _, exists = methods[method]; !exists {
+		return services.Errorf(services.CodeUnimplemented, "method %s of service %s is not supported by the daemon", method, name)
+	}
+	data, err := utils.Pack(st, method, req)
+	if err != nil {
+		return err
+	}
+	return utils.Send(client.c, data)
+}
+
+func closeFiles(files []*os.File) {
+	for _, f := range files {
+		f.Close()
+	}
+}
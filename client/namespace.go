@@ -1,8 +1,9 @@
 package client
 
 import (
-	"errors"
 	"fmt"
+	"os"
+	"time"
 
 	cerm "github.com/YLonely/cer-manager"
 	"github.com/YLonely/cer-manager/api/services/namespace"
@@ -11,52 +12,73 @@ import (
 )
 
 // GetNamespace get a namespace of type t of ref from cer-manager
-// if more than one reference is provided, the most fitting namespace among those references will be returned
-func (client *Client) GetNamespace(t types.NamespaceType, ref types.Reference, extraRefs ...types.Reference) (namespaceID int, namespacePath string, info interface{}, err error) {
-	req := namespace.GetNamespaceRequest{
+// if more than one reference is provided, the most fitting namespace among those references will be returned,
+// its info is then a types.Selection in JSON form naming the reference picked and why.
+// The namespace file is passed over the socket, the caller owns it and should close it after use.
+// The namespace is put back automatically if the client is closed before PutNamespace.
+func (client *Client) GetNamespace(t types.NamespaceType, ref types.Reference, extraRefs ...types.Reference) (namespaceID string, namespaceFile *os.File, info interface{}, err error) {
+	return client.getNamespace(namespace.GetNamespaceRequest{
 		T:         t,
 		Ref:       ref,
 		ExtraRefs: extraRefs,
-	}
-	var data []byte
-	data, err = utils.Pack(cerm.NamespaceService, namespace.MethodGetNamespace, req)
-	if err != nil {
-		return
-	}
-	if err = utils.Send(client.c, data); err != nil {
+	})
+}
+
+// GetNamespaceDetached is the same as GetNamespace but the namespace stays checked out after the client is closed,
+// it must be put back with PutNamespace
+func (client *Client) GetNamespaceDetached(t types.NamespaceType, ref types.Reference, extraRefs ...types.Reference) (namespaceID string, namespaceFile *os.File, info interface{}, err error) {
+	return client.getNamespace(namespace.GetNamespaceRequest{
+		T:         t,
+		Ref:       ref,
+		ExtraRefs: extraRefs,
+		Detach:    true,
+	})
+}
+
+func (client *Client) getNamespace(req namespace.GetNamespaceRequest) (namespaceID string, namespaceFile *os.File, info interface{}, err error) {
+	var rsp namespace.GetNamespaceResponse
+	if rsp, namespaceFile, err = client.GetNamespaceWithRequest(req); err != nil {
 		return
 	}
+	return rsp.ID, namespaceFile, rsp.Info, nil
+}
+
+// GetNamespaceWithRequest sends req as it is and returns the whole response, e.g. the lease on the namespace
+func (client *Client) GetNamespaceWithRequest(req namespace.GetNamespaceRequest) (namespace.GetNamespaceResponse, *os.File, error) {
 	rsp := namespace.GetNamespaceResponse{}
-	if err = utils.ReceiveObject(client.c, &rsp); err != nil {
-		return
+	if err := client.send(cerm.NamespaceService, namespace.MethodGetNamespace, req); err != nil {
+		return rsp, nil, err
 	}
-	if rsp.Fd == -1 {
-		return namespaceID, namespacePath, info, errors.New(rsp.Info.(string))
+	files, err := utils.ReceiveObjectWithFiles(client.c, &rsp)
+	if err != nil {
+		return rsp, nil, err
+	}
+	if rsp.Error != nil {
+		closeFiles(files)
+		return rsp, nil, rsp.Error
 	}
-	namespacePath = fmt.Sprintf("/proc/%d/fd/%d", rsp.Pid, rsp.Fd)
-	namespaceID = rsp.Fd
-	info = rsp.Info
-	return
+	if len(files) != 1 {
+		closeFiles(files)
+		return rsp, nil, fmt.Errorf("expect 1 namespace file but receive %d", len(files))
+	}
+	return rsp, files[0], nil
 }
 
-func (client *Client) PutNamespace(t types.NamespaceType, nsID int) error {
+func (client *Client) PutNamespace(t types.NamespaceType, nsID string) error {
 	req := namespace.PutNamespaceRequest{
 		T:  t,
 		ID: nsID,
 	}
-	data, err := utils.Pack(cerm.NamespaceService, namespace.MethodPutNamespace, req)
+	err := client.send(cerm.NamespaceService, namespace.MethodPutNamespace, req)
 	if err != nil {
 		return err
 	}
-	if err = utils.Send(client.c, data); err != nil {
-		return err
-	}
 	rsp := namespace.PutNamespaceResponse{}
 	if err = utils.ReceiveObject(client.c, &rsp); err != nil {
 		return err
 	}
-	if rsp.Error != "" {
-		return errors.New(rsp.Error)
+	if rsp.Error != nil {
+		return rsp.Error
 	}
 	return nil
 }
@@ -66,19 +88,56 @@ func (client *Client) UpdateNamespace(ref types.Reference, capacity int) error {
 		Ref:      ref,
 		Capacity: capacity,
 	}
-	data, err := utils.Pack(cerm.NamespaceService, namespace.MethodUpdateNamespace, req)
+	err := client.send(cerm.NamespaceService, namespace.MethodUpdateNamespace, req)
 	if err != nil {
 		return err
 	}
-	if err = utils.Send(client.c, data); err != nil {
-		return err
-	}
 	rsp := namespace.UpdateNamespaceResponse{}
 	if err = utils.ReceiveObject(client.c, &rsp); err != nil {
 		return err
 	}
-	if rsp.Error != "" {
-		return errors.New(rsp.Error)
+	if rsp.Error != nil {
+		return rsp.Error
 	}
 	return nil
 }
+
+// RenewNamespace extends the lease of id by ttl, the ttl of the lease is kept if ttl is zero
+func (client *Client) RenewNamespace(t types.NamespaceType, leaseID string, ttl time.Duration) (types.Lease, error) {
+	req := namespace.RenewNamespaceRequest{
+		T:       t,
+		LeaseID: leaseID,
+		TTL:     ttl,
+	}
+	err := client.send(cerm.NamespaceService, namespace.MethodRenewNamespace, req)
+	if err != nil {
+		return types.Lease{}, err
+	}
+	rsp := namespace.RenewNamespaceResponse{}
+	if err = utils.ReceiveObject(client.c, &rsp); err != nil {
+		return types.Lease{}, err
+	}
+	if rsp.Error != nil {
+		return types.Lease{}, rsp.Error
+	}
+	return rsp.Lease, nil
+}
+
+// InspectNamespace returns the state of the namespace managers of type t, or of all the managers if t is empty
+func (client *Client) InspectNamespace(t types.NamespaceType) (namespace.InspectNamespaceResponse, error) {
+	req := namespace.InspectNamespaceRequest{
+		T: t,
+	}
+	rsp := namespace.InspectNamespaceResponse{}
+	err := client.send(cerm.NamespaceService, namespace.MethodInspectNamespace, req)
+	if err != nil {
+		return rsp, err
+	}
+	if err = utils.ReceiveObject(client.c, &rsp); err != nil {
+		return rsp, err
+	}
+	if rsp.Error != nil {
+		return rsp, rsp.Error
+	}
+	return rsp, nil
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"fmt"
+	"os"
+
+	cerm "github.com/YLonely/cer-manager"
+	"github.com/YLonely/cer-manager/api/services/namespace"
+	"github.com/YLonely/cer-manager/api/types"
+	"github.com/YLonely/cer-manager/utils"
+)
+
+// GetSandbox checks out the namespaces of nsTypes and the checkpoint of ref all together, ipc, uts and mnt are used if nsTypes is empty.
+// The namespace files are returned in the same order as rsp.Namespaces, the caller owns them and should close them after use.
+// The sandbox is put back automatically if the client is closed before PutSandbox.
+func (client *Client) GetSandbox(ref types.Reference, nsTypes ...types.NamespaceType) (rsp namespace.GetSandboxResponse, files []*os.File, err error) {
+	return client.GetSandboxWithRequest(namespace.GetSandboxRequest{
+		Ref:   ref,
+		Types: nsTypes,
+	})
+}
+
+// GetSandboxWithRequest sends req as it is and returns the whole response
+func (client *Client) GetSandboxWithRequest(req namespace.GetSandboxRequest) (namespace.GetSandboxResponse, []*os.File, error) {
+	rsp := namespace.GetSandboxResponse{}
+	if err := client.send(cerm.NamespaceService, namespace.MethodGetSandbox, req); err != nil {
+		return rsp, nil, err
+	}
+	files, err := utils.ReceiveObjectWithFiles(client.c, &rsp)
+	if err != nil {
+		return rsp, nil, err
+	}
+	if rsp.Error != nil {
+		closeFiles(files)
+		return rsp, nil, rsp.Error
+	}
+	if len(files) != len(rsp.Namespaces) {
+		closeFiles(files)
+		return rsp, nil, fmt.Errorf("expect %d namespace files but receive %d", len(rsp.Namespaces), len(files))
+	}
+	return rsp, files, nil
+}
+
+// PutSandbox puts back all the namespaces and releases the checkpoint of the sandbox
+func (client *Client) PutSandbox(id string) error {
+	req := namespace.PutSandboxRequest{
+		ID: id,
+	}
+	err := client.send(cerm.NamespaceService, namespace.MethodPutSandbox, req)
+	if err != nil {
+		return err
+	}
+	rsp := namespace.PutSandboxResponse{}
+	if err = utils.ReceiveObject(client.c, &rsp); err != nil {
+		return err
+	}
+	if rsp.Error != nil {
+		return rsp.Error
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"time"
+
+	cpv1 "github.com/YLonely/cer-manager/api/services/checkpoint/v1"
+	"github.com/YLonely/cer-manager/api/services/namespace"
+	nsv1 "github.com/YLonely/cer-manager/api/services/namespace/v1"
+	"github.com/YLonely/cer-manager/api/types"
+	typesv1 "github.com/YLonely/cer-manager/api/types/v1"
+	"github.com/containerd/ttrpc"
+)
+
+const (
+	defaultTTRPCSocketPath = "/var/lib/cermanager/daemon.ttrpc.socket"
+)
+
+// NewTTRPC returns a client which talks to cer-manager over ttrpc
+func NewTTRPC(config Config) (*TTRPCClient, error) {
+	conn, err := net.Dial("unix", config.SocketPath)
+	if err != nil {
+		return nil, err
+	}
+	c := ttrpc.NewClient(conn)
+	return &TTRPCClient{
+		client:     c,
+		namespace:  nsv1.NewNamespaceClient(c),
+		checkpoint: cpv1.NewCheckpointClient(c),
+	}, nil
+}
+
+func DefaultTTRPC() (*TTRPCClient, error) {
+	return NewTTRPC(Config{
+		SocketPath: defaultTTRPCSocketPath,
+	})
+}
+
+// TTRPCClient provides the same methods as Client over ttrpc,
+// as ttrpc can not pass files the namespaces are returned as paths under the /proc of the daemon
+type TTRPCClient struct {
+	client     *ttrpc.Client
+	namespace  nsv1.NamespaceService
+	checkpoint cpv1.CheckpointService
+}
+
+func (client *TTRPCClient) Close() error {
+	return client.client.Close()
+}
+
+// NamespaceService returns the raw ttrpc client of the namespace service
+func (client *TTRPCClient) NamespaceService() nsv1.NamespaceService {
+	return client.namespace
+}
+
+// CheckpointService returns the raw ttrpc client of the checkpoint service
+func (client *TTRPCClient) CheckpointService() cpv1.CheckpointService {
+	return client.checkpoint
+}
+
+// GetNamespace get a namespace of type t of ref from cer-manager with a lease of the default ttl of the daemon,
+// which fails if the daemon has none. The namespace path can only be opened by root and is valid while the lease is held
+func (client *TTRPCClient) GetNamespace(ctx context.Context, t types.NamespaceType, ref types.Reference, extraRefs ...types.Reference) (namespaceID string, namespacePath string, info interface{}, err error) {
+	rsp, err := client.namespace.Get(ctx, &nsv1.GetNamespaceRequest{
+		NamespaceType: string(t),
+		Ref:           typesv1.FromReference(ref),
+		ExtraRefs:     typesv1.FromReferences(extraRefs),
+	})
+	if err != nil {
+		return "", "", nil, err
+	}
+	if err = rsp.Error.ToError(); err != nil {
+		return "", "", nil, err
+	}
+	if len(rsp.Info) != 0 {
+		if err = json.Unmarshal(rsp.Info, &info); err != nil {
+			return "", "", nil, err
+		}
+	}
+	return rsp.NamespaceID, rsp.NamespacePath, info, nil
+}
+
+func (client *TTRPCClient) PutNamespace(ctx context.Context, t types.NamespaceType, nsID string) error {
+	rsp, err := client.namespace.Put(ctx, &nsv1.PutNamespaceRequest{
+		NamespaceType: string(t),
+		NamespaceID:   nsID,
+	})
+	if err != nil {
+		return err
+	}
+	return rsp.Error.ToError()
+}
+
+func (client *TTRPCClient) UpdateNamespace(ctx context.Context, ref types.Reference, capacity int) error {
+	rsp, err := client.namespace.Update(ctx, &nsv1.UpdateNamespaceRequest{
+		Ref:      typesv1.FromReference(ref),
+		Capacity: int64(capacity),
+	})
+	if err != nil {
+		return err
+	}
+	return rsp.Error.ToError()
+}
+
+// GetCheckpoint returns the dir path in which checkpoint files of the container with ref located
+func (client *TTRPCClient) GetCheckpoint(ctx context.Context, ref types.Reference) (string, error) {
+	rsp, err := client.checkpoint.Get(ctx, &cpv1.GetCheckpointRequest{
+		Ref: typesv1.FromReference(ref),
+	})
+	if err != nil {
+		return "", err
+	}
+	if err = rsp.Error.ToError(); err != nil {
+		return "", err
+	}
+	return rsp.Path, nil
+}
+
+// PutCheckpoint release the checkpoint
+func (client *TTRPCClient) PutCheckpoint(ctx context.Context, ref types.Reference) error {
+	rsp, err := client.checkpoint.Put(ctx, &cpv1.PutCheckpointRequest{
+		Ref: typesv1.FromReference(ref),
+	})
+	if err != nil {
+		return err
+	}
+	return rsp.Error.ToError()
+}
+
+// RenewNamespace extends the lease of id by ttl, the ttl of the lease is kept if ttl is zero
+func (client *TTRPCClient) RenewNamespace(ctx context.Context, t types.NamespaceType, leaseID string, ttl time.Duration) (types.Lease, error) {
+	rsp, err := client.namespace.Renew(ctx, &nsv1.RenewNamespaceRequest{
+		NamespaceType: string(t),
+		LeaseID:       leaseID,
+		TTL:           int64(ttl),
+	})
+	if err != nil {
+		return types.Lease{}, err
+	}
+	if err = rsp.Error.ToError(); err != nil {
+		return types.Lease{}, err
+	}
+	return rsp.Lease.ToLease(), nil
+}
+
+// InspectNamespace returns the state of the namespace managers of type t, or of all the managers if t is empty
+func (client *TTRPCClient) InspectNamespace(ctx context.Context, t types.NamespaceType) (namespace.InspectNamespaceResponse, error) {
+	var ret namespace.InspectNamespaceResponse
+	rsp, err := client.namespace.Inspect(ctx, &nsv1.InspectNamespaceRequest{
+		NamespaceType: string(t),
+	})
+	if err != nil {
+		return ret, err
+	}
+	if err = rsp.Error.ToError(); err != nil {
+		return ret, err
+	}
+	err = json.Unmarshal(rsp.Status, &ret)
+	return ret, err
+}
+
+// GetSandbox checks out the namespaces of nsTypes and the checkpoint of ref all together, ipc, uts and mnt are used if nsTypes is empty
+func (client *TTRPCClient) GetSandbox(ctx context.Context, ref types.Reference, nsTypes ...types.NamespaceType) (*nsv1.GetSandboxResponse, error) {
+	req := &nsv1.GetSandboxRequest{
+		Ref: typesv1.FromReference(ref),
+	}
+	for _, t := range nsTypes {
+		req.NamespaceTypes = append(req.NamespaceTypes, string(t))
+	}
+	rsp, err := client.namespace.GetSandbox(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err = rsp.Error.ToError(); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+// PutSandbox puts back all the namespaces and releases the checkpoint of the sandbox
+func (client *TTRPCClient) PutSandbox(ctx context.Context, id string) error {
+	rsp, err := client.namespace.PutSandbox(ctx, &nsv1.PutSandboxRequest{
+		SandboxID: id,
+	})
+	if err != nil {
+		return err
+	}
+	return rsp.Error.ToError()
+}
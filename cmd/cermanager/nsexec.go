@@ -12,7 +12,7 @@ import (
 var nsexecCommand = cli.Command{
 	Name:      "nsexec",
 	Usage:     "execute functions in a namespace",
-	ArgsUsage: "FUNCTION_KEY NSTYPE {mnt|ipc|uts}",
+	ArgsUsage: "FUNCTION_KEY NSTYPE {mnt|ipc|uts|net|cgroup|user|time}",
 	Flags: []cli.Flag{
 		cli.StringFlag{
 			Name:  "src",
@@ -24,7 +24,23 @@ var nsexecCommand = cli.Command{
 		},
 		cli.StringFlag{
 			Name:  "checkpoint",
-			Usage: "specifiy the path to the checkpoint files if the type is mnt",
+			Usage: "specifiy the path to the checkpoint files if the type is mnt, ipc, net or time",
+		},
+		cli.StringFlag{
+			Name:  "checkpoints",
+			Usage: "specifiy the checkpoints whose objects are merged into the namespace if the type is ipc, separated by ':'",
+		},
+		cli.StringFlag{
+			Name:  "template",
+			Usage: "specifiy the bundle of the namespace cloned if the type is mnt",
+		},
+		cli.StringFlag{
+			Name:  "hostname",
+			Usage: "specifiy the hostname to reset to if the type is uts",
+		},
+		cli.StringFlag{
+			Name:  "domainname",
+			Usage: "specifiy the domainname to reset to if the type is uts",
 		},
 	},
 	Action: func(context *cli.Context) error {
@@ -40,9 +56,13 @@ var nsexecCommand = cli.Command{
 		if f != nil {
 			ret, err = f(
 				map[string]interface{}{
-					"src":        context.String("src"),
-					"bundle":     context.String("bundle"),
-					"checkpoint": context.String("checkpoint"),
+					"src":         context.String("src"),
+					"bundle":      context.String("bundle"),
+					"checkpoint":  context.String("checkpoint"),
+					"checkpoints": context.String("checkpoints"),
+					"template":    context.String("template"),
+					"hostname":    context.String("hostname"),
+					"domainname":  context.String("domainname"),
 				},
 			)
 			if err != nil {
@@ -4,10 +4,12 @@ import (
 	"context"
 	"os"
 	"os/signal"
+	"strconv"
 
 	"github.com/YLonely/cer-manager/cermanager"
 	"github.com/YLonely/cer-manager/log"
 	"github.com/YLonely/cer-manager/signals"
+	"github.com/pkg/errors"
 	"github.com/urfave/cli"
 )
 
@@ -19,6 +21,18 @@ var startCommand = cli.Command{
 			Name:  "http-port",
 			Usage: "enable the http server of cer-manager on [port]",
 		},
+		cli.StringFlag{
+			Name:  "socket-mode",
+			Usage: "file mode of the daemon sockets in octal, e.g. 0660",
+		},
+		cli.StringFlag{
+			Name:  "socket-group",
+			Usage: "name or id of the group owning the daemon sockets",
+		},
+		cli.StringFlag{
+			Name:  "policy",
+			Usage: "path of the access policy file, all the peers are allowed if not set",
+		},
 	},
 	Action: func(c *cli.Context) error {
 		if c.GlobalBool("debug") {
@@ -26,9 +40,21 @@ var startCommand = cli.Command{
 		} else {
 			log.SetLevel(log.LevelInfo)
 		}
+		config := cermanager.Config{
+			HTTPPort:    c.Int("http-port"),
+			SocketGroup: c.String("socket-group"),
+			PolicyPath:  c.String("policy"),
+		}
+		if mode := c.String("socket-mode"); mode != "" {
+			m, err := strconv.ParseUint(mode, 8, 32)
+			if err != nil {
+				return errors.Wrapf(err, "invalid socket mode %s", mode)
+			}
+			config.SocketMode = os.FileMode(m)
+		}
 		signalC := make(chan os.Signal, 2048)
 		ctx, cancel := context.WithCancel(context.Background())
-		s, err := cermanager.NewServer(c.Int("http-port"))
+		s, err := cermanager.NewServer(config)
 		if err != nil {
 			cancel()
 			return err
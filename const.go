@@ -13,10 +13,20 @@ const (
 	NamespaceService ServiceType = iota + 10
 	CheckpointService
 	HttpService
+	HandshakeService
 )
 
 var Type2Services = map[ServiceType]string{
 	NamespaceService:  "namespace",
 	CheckpointService: "checkpoint",
 	HttpService:       "http",
+	HandshakeService:  "handshake",
 }
+
+const (
+	// ProtocolVersion is the newest version of the protocol spoken on the daemon socket,
+	// version 2 replaces the namespace fd with an opaque handle
+	ProtocolVersion uint16 = 2
+	// MinProtocolVersion is the oldest version of the protocol that is still understood
+	MinProtocolVersion uint16 = 2
+)
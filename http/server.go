@@ -10,6 +10,7 @@ import (
 	"path"
 
 	cerm "github.com/YLonely/cer-manager"
+	apiservices "github.com/YLonely/cer-manager/api/services"
 	"github.com/YLonely/cer-manager/api/types"
 	"github.com/YLonely/cer-manager/client"
 	"github.com/YLonely/cer-manager/log"
@@ -38,6 +39,7 @@ func NewServer(root string, port int) (*Server, error) {
 		s:    s,
 	}
 	http.HandleFunc("/namespace/update", ret.updateNamespace)
+	http.HandleFunc("/namespace/inspect", ret.inspectNamespace)
 	return ret, nil
 }
 
@@ -92,3 +94,28 @@ func (svr *Server) updateNamespace(w gohttp.ResponseWriter, r *gohttp.Request) {
 		log.Logger(cerm.HttpService, "updateNamespace").Error(err)
 	}
 }
+
+// inspectNamespace returns the state of the namespace managers, the namespace type can be set by the query "type"
+func (svr *Server) inspectNamespace(w gohttp.ResponseWriter, r *gohttp.Request) {
+	if r.Method != gohttp.MethodGet {
+		w.WriteHeader(gohttp.StatusMethodNotAllowed)
+		return
+	}
+	c, err := client.Default()
+	if err != nil {
+		log.Logger(cerm.HttpService, "inspectNamespace").WithError(err).Error("failed to create cer-manager client")
+		w.WriteHeader(gohttp.StatusInternalServerError)
+		return
+	}
+	defer c.Close()
+	resp, err := c.InspectNamespace(types.NamespaceType(r.URL.Query().Get("type")))
+	if err != nil {
+		resp.Error = apiservices.ToError(err)
+		w.WriteHeader(gohttp.StatusBadRequest)
+	} else {
+		w.WriteHeader(gohttp.StatusOK)
+	}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Logger(cerm.HttpService, "inspectNamespace").Error(err)
+	}
+}
@@ -0,0 +1,196 @@
+package namespace
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	apiservices "github.com/YLonely/cer-manager/api/services"
+	"github.com/YLonely/cer-manager/api/types"
+	"github.com/YLonely/cer-manager/log"
+)
+
+const (
+	// AutoscaleInterval is the window in which the demand of a reference is measured
+	AutoscaleInterval = 10 * time.Second
+	// checkoutWeight is the weight of the latest window in the average checkout duration
+	checkoutWeight = 0.5
+)
+
+// NewAutoscaler returns an Autoscaler of the namespaces of type t and starts it,
+// resize is called with the new capacity of the namespace set of a reference
+func NewAutoscaler(t types.NamespaceType, resize func(ref types.Reference, capacity int) error) *Autoscaler {
+	a := &Autoscaler{
+		t:         t,
+		refs:      map[string]*demand{},
+		checkouts: map[string]checkout{},
+		resize:    resize,
+		stopC:     make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+// Autoscaler grows or shrinks the namespace set of each reference between its bounds following the demand.
+// The capacity of a set is the expected number of namespaces checked out at the same time,
+// which is the rate of Get times the average checkout duration, plus the number of Get missed in the last window.
+type Autoscaler struct {
+	m sync.Mutex
+	t types.NamespaceType
+	// refs maps the digest of a reference to its demand
+	refs map[string]*demand
+	// checkouts maps the handle of a namespace checked out to its checkout
+	checkouts map[string]checkout
+	resize    func(ref types.Reference, capacity int) error
+	stopC     chan struct{}
+	stopOnce  sync.Once
+}
+
+type demand struct {
+	ref      types.Reference
+	min, max int
+	capacity int
+	gets     int
+	misses   int
+	// duration is the moving average of the checkout duration, zero if no namespace is returned yet
+	duration time.Duration
+	returned []time.Duration
+}
+
+type checkout struct {
+	digest string
+	start  time.Time
+}
+
+// Enable starts scaling the namespace set of ref between min and max, capacity is the current capacity of the set
+func (a *Autoscaler) Enable(ref types.Reference, capacity, min, max int) error {
+	if min < 0 || max <= 0 || min > max {
+		return apiservices.Errorf(apiservices.CodeInvalidArgument, "invalid capacity bounds [%d, %d] of %s", min, max, ref)
+	}
+	a.m.Lock()
+	defer a.m.Unlock()
+	a.refs[ref.Digest()] = &demand{
+		ref:      ref,
+		min:      min,
+		max:      max,
+		capacity: capacity,
+	}
+	return nil
+}
+
+// Enabled returns whether the namespace set of ref is scaled by the autoscaler
+func (a *Autoscaler) Enabled(ref types.Reference) bool {
+	a.m.Lock()
+	defer a.m.Unlock()
+	_, exists := a.refs[ref.Digest()]
+	return exists
+}
+
+// Checkout records a namespace of ref checked out with handle
+func (a *Autoscaler) Checkout(handle string, ref types.Reference) {
+	a.m.Lock()
+	defer a.m.Unlock()
+	d, exists := a.refs[ref.Digest()]
+	if !exists {
+		return
+	}
+	d.gets++
+	a.checkouts[handle] = checkout{digest: ref.Digest(), start: time.Now()}
+}
+
+// Return records the namespace of handle put back
+func (a *Autoscaler) Return(handle string) {
+	a.m.Lock()
+	defer a.m.Unlock()
+	c, exists := a.checkouts[handle]
+	if !exists {
+		return
+	}
+	delete(a.checkouts, handle)
+	if d, exists := a.refs[c.digest]; exists {
+		d.returned = append(d.returned, time.Since(c.start))
+	}
+}
+
+// Miss records a Get of ref that finds no namespace available
+func (a *Autoscaler) Miss(ref types.Reference) {
+	a.m.Lock()
+	defer a.m.Unlock()
+	if d, exists := a.refs[ref.Digest()]; exists {
+		d.misses++
+	}
+}
+
+// Stop stops the autoscaler
+func (a *Autoscaler) Stop() {
+	a.stopOnce.Do(func() { close(a.stopC) })
+}
+
+func (a *Autoscaler) run() {
+	ticker := time.NewTicker(AutoscaleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-a.stopC:
+			return
+		case <-ticker.C:
+		}
+		// resize without holding the lock, the manager locks itself first
+		for _, d := range a.scale() {
+			if err := a.resize(d.ref, d.capacity); err != nil {
+				log.Raw().WithError(err).Errorf("failed to resize the %s namespace set of %s to %d", a.t, d.ref, d.capacity)
+			}
+		}
+	}
+}
+
+// scale computes the capacity of each reference from the demand of the last window
+// and returns the references whose capacity changes
+func (a *Autoscaler) scale() []demand {
+	a.m.Lock()
+	defer a.m.Unlock()
+	now := time.Now()
+	var changed []demand
+	for digest, d := range a.refs {
+		for _, c := range a.checkouts {
+			// the namespaces still checked out take at least the time since they are checked out
+			if c.digest == digest {
+				if held := now.Sub(c.start); held > AutoscaleInterval {
+					d.returned = append(d.returned, held)
+				}
+			}
+		}
+		if len(d.returned) > 0 {
+			var sum time.Duration
+			for _, r := range d.returned {
+				sum += r
+			}
+			avg := sum / time.Duration(len(d.returned))
+			if d.duration == 0 {
+				d.duration = avg
+			} else {
+				d.duration = time.Duration(checkoutWeight*float64(avg) + (1-checkoutWeight)*float64(d.duration))
+			}
+		}
+		duration := d.duration
+		if duration == 0 {
+			// assume the namespaces are held for a whole window before any of them is returned
+			duration = AutoscaleInterval
+		}
+		rate := float64(d.gets) / AutoscaleInterval.Seconds()
+		capacity := int(math.Ceil(rate*duration.Seconds())) + d.misses
+		if capacity < d.min {
+			capacity = d.min
+		}
+		if capacity > d.max {
+			capacity = d.max
+		}
+		d.gets, d.misses, d.returned = 0, 0, nil
+		if capacity != d.capacity {
+			log.Raw().Debugf("scale the %s namespace set of %s from %d to %d", a.t, d.ref, d.capacity, capacity)
+			d.capacity = capacity
+			changed = append(changed, *d)
+		}
+	}
+	return changed
+}
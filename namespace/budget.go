@@ -0,0 +1,169 @@
+package namespace
+
+import (
+	"os"
+	"sync"
+
+	apiservices "github.com/YLonely/cer-manager/api/services"
+	"github.com/YLonely/cer-manager/api/types"
+)
+
+// NewBudget returns a Budget with limits, a zero limit means unlimited
+func NewBudget(limits types.Resources) *Budget {
+	return &Budget{
+		limits: limits,
+	}
+}
+
+// Budget limits the resources held by the namespaces of all the references and types
+type Budget struct {
+	m      sync.Mutex
+	limits types.Resources
+	used   types.Resources
+}
+
+// Acquire charges cost to the budget, the error names the limit exceeded if it does not fit
+func (b *Budget) Acquire(cost types.Resources) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+	if err := b.admit(cost, 1); err != nil {
+		return err
+	}
+	b.used = b.used.Add(cost)
+	return nil
+}
+
+// Release gives cost back to the budget
+func (b *Budget) Release(cost types.Resources) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	b.used = b.used.Sub(cost)
+}
+
+func (b *Budget) charge(cost types.Resources) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	b.used = b.used.Add(cost)
+}
+
+// Admit checks if n more namespaces of cost fit in the budget without charging them
+func (b *Budget) Admit(cost types.Resources, n int) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+	return b.admit(cost, n)
+}
+
+func (b *Budget) admit(cost types.Resources, n int) error {
+	exceeded := func(name string, used, more, limit int64) error {
+		if limit <= 0 || used+more <= limit {
+			return nil
+		}
+		return apiservices.Errorf(apiservices.CodeExhausted, "budget of %s is exceeded, %d used and %d more requested but the limit is %d", name, used, more, limit)
+	}
+	if err := exceeded("namespaces", int64(b.used.Namespaces), int64(cost.Namespaces*n), int64(b.limits.Namespaces)); err != nil {
+		return err
+	}
+	if err := exceeded("overlay upper bytes", b.used.UpperBytes, cost.UpperBytes*int64(n), b.limits.UpperBytes); err != nil {
+		return err
+	}
+	return exceeded("shm bytes", b.used.ShmBytes, cost.ShmBytes*int64(n), b.limits.ShmBytes)
+}
+
+// Status returns the usage of the budget
+func (b *Budget) Status() types.BudgetStatus {
+	b.m.Lock()
+	defer b.m.Unlock()
+	return types.BudgetStatus{
+		Limits: b.limits,
+		Used:   b.used,
+	}
+}
+
+// Meter returns a Meter charging the namespaces of a set to the budget, cost is the estimated cost of a namespace.
+// If measure is not nil, it returns the real cost of a namespace just created
+func (b *Budget) Meter(cost types.Resources, measure func(*os.File) types.Resources) *Meter {
+	return &Meter{
+		b:        b,
+		estimate: cost,
+		measure:  measure,
+		charges:  map[*os.File]types.Resources{},
+	}
+}
+
+// Meter charges the namespaces of a set to a budget from their creation to their release
+type Meter struct {
+	b *Budget
+	m sync.Mutex
+	// estimate is charged before a namespace is created, it follows the cost measured last
+	estimate types.Resources
+	measure  func(*os.File) types.Resources
+	charges  map[*os.File]types.Resources
+}
+
+// Admit checks if n more namespaces fit in the budget
+func (m *Meter) Admit(n int) error {
+	m.m.Lock()
+	cost := m.estimate
+	m.m.Unlock()
+	return m.b.Admit(cost, n)
+}
+
+// Creator wraps create so that each namespace is admitted against the budget before it is created
+func (m *Meter) Creator(create func() (*os.File, error)) func() (*os.File, error) {
+	return func() (*os.File, error) {
+		m.m.Lock()
+		cost := m.estimate
+		m.m.Unlock()
+		if err := m.b.Acquire(cost); err != nil {
+			return nil, err
+		}
+		f, err := create()
+		if err != nil {
+			m.b.Release(cost)
+			return nil, err
+		}
+		if m.measure != nil {
+			measured := m.measure(f)
+			// the namespace is created already, charge it even if it goes over the budget
+			m.b.charge(measured.Sub(cost))
+			cost = measured
+		}
+		m.m.Lock()
+		m.estimate = cost
+		m.charges[f] = cost
+		m.m.Unlock()
+		return f, nil
+	}
+}
+
+// Remeasure measures f again and charges the difference from its last cost to the budget
+func (m *Meter) Remeasure(f *os.File) {
+	if m.measure == nil {
+		return
+	}
+	measured := m.measure(f)
+	m.m.Lock()
+	cost, exists := m.charges[f]
+	if exists {
+		m.charges[f] = measured
+	}
+	m.m.Unlock()
+	if exists {
+		m.b.charge(measured.Sub(cost))
+	}
+}
+
+// Releaser wraps release so that the cost of each namespace released is given back to the budget
+func (m *Meter) Releaser(release func(*os.File) error) func(*os.File) error {
+	return func(f *os.File) error {
+		err := release(f)
+		m.m.Lock()
+		cost, exists := m.charges[f]
+		delete(m.charges, f)
+		m.m.Unlock()
+		if exists {
+			m.b.Release(cost)
+		}
+		return err
+	}
+}
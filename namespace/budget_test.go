@@ -0,0 +1,41 @@
+package namespace
+
+import (
+	"os"
+	"testing"
+
+	"github.com/YLonely/cer-manager/api/types"
+)
+
+func TestMeterRemeasure(t *testing.T) {
+	b := NewBudget(types.Resources{UpperBytes: 100})
+	upper := int64(10)
+	meter := b.Meter(types.Resources{Namespaces: 1}, func(*os.File) types.Resources {
+		return types.Resources{Namespaces: 1, UpperBytes: upper}
+	})
+	f, err := meter.Creator(func() (*os.File, error) { return os.Open(os.DevNull) })()
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps := []struct {
+		upper int64
+		want  int64
+	}{
+		{upper: 60, want: 60},
+		{upper: 20, want: 20},
+		{upper: 120, want: 120},
+	}
+	for _, step := range steps {
+		upper = step.upper
+		meter.Remeasure(f)
+		if used := b.Status().Used.UpperBytes; used != step.want {
+			t.Fatalf("%d upper bytes are charged after remeasuring %d, want %d", used, step.upper, step.want)
+		}
+	}
+	if err = meter.Releaser(func(f *os.File) error { return f.Close() })(f); err != nil {
+		t.Fatal(err)
+	}
+	if used := b.Status().Used; used != (types.Resources{}) {
+		t.Fatalf("%+v is still charged after the release", used)
+	}
+}
@@ -0,0 +1,157 @@
+package cgroup
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	apiservices "github.com/YLonely/cer-manager/api/services"
+	"github.com/YLonely/cer-manager/api/types"
+	"github.com/YLonely/cer-manager/log"
+	"github.com/YLonely/cer-manager/namespace"
+	"github.com/pkg/errors"
+)
+
+// ErrNotSupported is returned by NewManager if cgroup v2 is not mounted
+var ErrNotSupported = errors.New("cgroup v2 is not mounted")
+
+// DefaultSubtree returns the subtree the cgroup namespaces of ref are rooted at if none is chosen
+func DefaultSubtree(ref types.Reference) string {
+	return path.Join("cermanager", ref.Digest())
+}
+
+// NewManager returns the manager of the cgroup namespaces of refs, the namespaces of each reference
+// are rooted at its subtree in subtrees, which is relative to the root of cgroup v2
+func NewManager(capacities []int, refs []types.Reference, subtrees []string, refiller *namespace.Refiller, budget *namespace.Budget) (namespace.Manager, error) {
+	root, err := unifiedRoot()
+	if err != nil {
+		return nil, err
+	}
+	pools, err := namespace.NewPoolManager(namespace.PoolConfig{
+		Type:     types.NamespaceCGROUP,
+		Refiller: refiller,
+		Budget:   budget,
+	})
+	if err != nil {
+		return nil, err
+	}
+	m := &manager{
+		PoolManager: pools,
+		root:        root,
+	}
+	for i, ref := range refs {
+		if err := m.add(ref, capacities[i], subtrees[i]); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+type manager struct {
+	*namespace.PoolManager
+	// root is the mount point of cgroup v2
+	root string
+	// dirs are the cgroups the namespaces of the references are rooted at
+	dirs []string
+}
+
+// add creates the cgroup of ref under subtree and the pool of the namespaces rooted at it
+func (m *manager) add(ref types.Reference, capacity int, subtree string) error {
+	if subtree == "" {
+		subtree = DefaultSubtree(ref)
+	}
+	dir := path.Join(m.root, path.Clean("/"+subtree))
+	if dir == m.root {
+		return apiservices.Errorf(apiservices.CodeInvalidArgument, "cgroup namespaces of %s can not be rooted at the root cgroup", ref)
+	}
+	if err := checkAncestors(m.root, dir); err != nil {
+		return errors.Wrapf(err, "cgroup %s for %s can not hold the containers", dir, ref)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrapf(err, "failed to create cgroup %s for %s", dir, ref)
+	}
+	m.dirs = append(m.dirs, dir)
+	return m.Add(ref, namespace.Pool{
+		Capacity: capacity,
+		Cost:     types.Resources{Namespaces: 1},
+		Create:   makeCgroupNamespaceCreator(dir),
+		Check: func(*os.File) error {
+			_, err := os.Stat(path.Join(dir, "cgroup.procs"))
+			return errors.Wrapf(err, "cgroup %s is gone", dir)
+		},
+		// a cgroup namespace holds nothing but its root, so it is reused as is
+		Put: namespace.PutRecycle,
+		// the container is expected to be put in the cgroup the namespace is rooted at
+		Info: func(*os.File) interface{} { return dir },
+	})
+}
+
+func (m *manager) CleanUp() error {
+	last := m.PoolManager.CleanUp()
+	for _, dir := range m.dirs {
+		// the cgroup is left if the containers in it are still running
+		if err := os.Remove(dir); err != nil {
+			log.Raw().WithError(err).Warnf("failed to remove cgroup %s", dir)
+		}
+	}
+	return last
+}
+
+func makeCgroupNamespaceCreator(dir string) func() (*os.File, error) {
+	return func() (*os.File, error) {
+		h, err := namespace.NewNamespaceExecCreateHelper(namespace.NamespaceFunctionKeyCreate, types.NamespaceCGROUP, nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create cgroup create helper")
+		}
+		h.JoinCgroup(dir)
+		if err = h.Do(false); err != nil {
+			return nil, errors.Wrap(err, "failed to create new CGROUP namespace")
+		}
+		defer h.Release()
+		nsFile, err := namespace.OpenNSFile(types.NamespaceCGROUP, h.Cmd.Process.Pid)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to open CGROUP namespace")
+		}
+		return nsFile, nil
+	}
+}
+
+// checkAncestors checks that the ancestors of dir below root have no processes, as the containers put in dir
+// need the controllers enabled in them and cgroup v2 only allows that in the cgroups without processes
+func checkAncestors(root, dir string) error {
+	for p := path.Dir(dir); p != root && strings.HasPrefix(p, root); p = path.Dir(p) {
+		data, err := ioutil.ReadFile(path.Join(p, "cgroup.procs"))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if len(strings.TrimSpace(string(data))) != 0 {
+			return errors.Errorf("its ancestor %s has processes", p)
+		}
+	}
+	return nil
+}
+
+// unifiedRoot returns the mount point of cgroup v2
+func unifiedRoot() (string, error) {
+	f, err := os.Open("/proc/self/mounts")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 3 && fields[2] == "cgroup2" {
+			return fields[1], nil
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", ErrNotSupported
+}
@@ -0,0 +1,46 @@
+package cgroup
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestCheckAncestors(t *testing.T) {
+	tests := []struct {
+		name string
+		// procs maps a cgroup relative to the root to its cgroup.procs
+		procs map[string]string
+		dir   string
+		ok    bool
+	}{
+		{name: "empty ancestors", procs: map[string]string{"a": "", "a/b": ""}, dir: "a/b/c", ok: true},
+		{name: "processes in the root", procs: map[string]string{"": "1\n", "a": ""}, dir: "a/b", ok: true},
+		{name: "processes in the parent", procs: map[string]string{"a": "", "a/b": "42\n"}, dir: "a/b/c", ok: false},
+		{name: "processes in an ancestor", procs: map[string]string{"a": "42\n", "a/b": ""}, dir: "a/b/c", ok: false},
+		{name: "processes in dir itself", procs: map[string]string{"a": "", "a/b": "42\n"}, dir: "a/b", ok: true},
+		{name: "ancestors to be created", procs: map[string]string{}, dir: "a/b/c", ok: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := ioutil.TempDir("", "cgroup")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(root)
+			for cg, procs := range tt.procs {
+				if err = os.MkdirAll(path.Join(root, cg), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err = ioutil.WriteFile(path.Join(root, cg, "cgroup.procs"), []byte(procs), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			err = checkAncestors(root, path.Join(root, tt.dir))
+			if (err == nil) != tt.ok {
+				t.Fatalf("checkAncestors() = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
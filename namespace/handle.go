@@ -0,0 +1,78 @@
+package namespace
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+
+	apiservices "github.com/YLonely/cer-manager/api/services"
+	"github.com/YLonely/cer-manager/api/types"
+	"github.com/YLonely/cer-manager/utils"
+	"github.com/pkg/errors"
+)
+
+// NewHandleIssuer returns a HandleIssuer of the namespaces of type t
+func NewHandleIssuer(t types.NamespaceType) (*HandleIssuer, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return &HandleIssuer{
+		t:      t,
+		prefix: fmt.Sprintf("%s-%s-", t, hex.EncodeToString(b)),
+		issued: map[string]struct{}{},
+	}, nil
+}
+
+// maxIssued is the number of the handles a HandleIssuer remembers to tell the stale handles from the invalid ones
+const maxIssued = 1 << 16
+
+// HandleIssuer issues the handles of the namespaces checked out from a manager.
+// A handle is made up of the namespace type, a random instance id and a random part,
+// so it is never reused even after the daemon restarts and can not be guessed.
+type HandleIssuer struct {
+	m      sync.Mutex
+	t      types.NamespaceType
+	prefix string
+	// issued are the last maxIssued handles issued, oldest first in order
+	issued map[string]struct{}
+	order  []string
+}
+
+// Next returns a new handle
+func (h *HandleIssuer) Next() (string, error) {
+	id, err := utils.RandomID()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to generate the handle")
+	}
+	handle := h.prefix + id
+	h.m.Lock()
+	defer h.m.Unlock()
+	if len(h.order) == maxIssued {
+		delete(h.issued, h.order[0])
+		h.order = h.order[1:]
+	}
+	h.issued[handle] = struct{}{}
+	h.order = append(h.order, handle)
+	return handle, nil
+}
+
+// Unknown returns the error for a handle which is not in use, it tells whether the handle is stale or invalid.
+// The handles issued long ago are taken as invalid
+func (h *HandleIssuer) Unknown(handle string) error {
+	if !strings.HasPrefix(handle, h.prefix) {
+		if strings.HasPrefix(handle, string(h.t)+"-") {
+			return apiservices.Errorf(apiservices.CodeNotFound, "%s namespace handle %s is issued by another instance of the daemon", h.t, handle)
+		}
+		return apiservices.Errorf(apiservices.CodeInvalidArgument, "invalid %s namespace handle %s", h.t, handle)
+	}
+	h.m.Lock()
+	_, issued := h.issued[handle]
+	h.m.Unlock()
+	if !issued {
+		return apiservices.Errorf(apiservices.CodeInvalidArgument, "invalid %s namespace handle %s", h.t, handle)
+	}
+	return apiservices.Errorf(apiservices.CodeNotFound, "%s namespace handle %s is stale, the namespace has been put back", h.t, handle)
+}
@@ -0,0 +1,126 @@
+package namespace
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/YLonely/cer-manager/api/types"
+	"github.com/YLonely/cer-manager/log"
+)
+
+// HealthCheckInterval is the interval between two rounds of checks of the idle namespaces
+const HealthCheckInterval = time.Minute
+
+// NewHealthChecker returns a HealthChecker of the namespaces of type t and starts it
+func NewHealthChecker(t types.NamespaceType, interval time.Duration) *HealthChecker {
+	h := &HealthChecker{
+		t:        t,
+		interval: interval,
+		targets:  map[string]*healthTarget{},
+		stopC:    make(chan struct{}),
+	}
+	go h.run()
+	return h
+}
+
+// HealthChecker checks the idle namespaces of the sets periodically, an unhealthy namespace is evicted
+// from its set and released, and the set is refilled with a new one
+type HealthChecker struct {
+	m        sync.Mutex
+	t        types.NamespaceType
+	interval time.Duration
+	// targets maps the digest of a reference to the set checked
+	targets  map[string]*healthTarget
+	stopC    chan struct{}
+	stopOnce sync.Once
+}
+
+type healthTarget struct {
+	set    *Set
+	check  func(*os.File) error
+	status types.HealthStatus
+}
+
+// Watch checks the idle namespaces in set of ref with check, a namespace is unhealthy if check returns an error
+func (h *HealthChecker) Watch(ref types.Reference, set *Set, check func(*os.File) error) {
+	h.m.Lock()
+	defer h.m.Unlock()
+	h.targets[ref.Digest()] = &healthTarget{
+		set:   set,
+		check: check,
+		status: types.HealthStatus{
+			NamespaceType: h.t,
+			Ref:           ref,
+		},
+	}
+}
+
+// Status returns the results of the checks of each reference
+func (h *HealthChecker) Status() []types.HealthStatus {
+	h.m.Lock()
+	defer h.m.Unlock()
+	ret := make([]types.HealthStatus, 0, len(h.targets))
+	for _, target := range h.targets {
+		ret = append(ret, target.status)
+	}
+	return ret
+}
+
+// Stop stops the health checker
+func (h *HealthChecker) Stop() {
+	h.stopOnce.Do(func() { close(h.stopC) })
+}
+
+func (h *HealthChecker) run() {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-h.stopC:
+			return
+		case <-ticker.C:
+		}
+		h.m.Lock()
+		targets := make([]*healthTarget, 0, len(h.targets))
+		for _, target := range h.targets {
+			targets = append(targets, target)
+		}
+		h.m.Unlock()
+		for _, target := range targets {
+			h.checkSet(target)
+		}
+	}
+}
+
+// checkSet checks the namespaces in the set of target without holding the lock, as a check may take a while
+func (h *HealthChecker) checkSet(target *healthTarget) {
+	var checked, unhealthy int
+	var last error
+	for _, f := range target.set.Idle() {
+		checked++
+		err := target.check(f)
+		if err == nil {
+			continue
+		}
+		// the namespace is left to the one taking it during the check
+		if !target.set.Evict(f) {
+			continue
+		}
+		unhealthy++
+		last = err
+		log.Raw().WithError(err).Warnf("%s namespace of %s is unhealthy, evict it", h.t, target.status.Ref)
+		if err := target.set.Release(f); err != nil {
+			log.Raw().WithError(err).Errorf("failed to release the unhealthy %s namespace of %s", h.t, target.status.Ref)
+		}
+	}
+	h.m.Lock()
+	defer h.m.Unlock()
+	target.status.Checked = checked
+	target.status.Unhealthy = unhealthy
+	target.status.Evicted += unhealthy
+	target.status.LastCheck = time.Now()
+	if last != nil {
+		target.status.LastError = last.Error()
+	}
+}
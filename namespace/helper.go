@@ -21,8 +21,13 @@ const (
 	nsexecOpKey     string = "__OP_TYPE__"
 	nsexecOpCreate  string = "CREATE"
 	nsexecOpEnter   string = "ENTER"
+	nsexecOpClone   string = "CLONE"
 	nsexecNSTypeKey string = "__NS_TYPE__"
 	nsexecNSPathKey string = "__NS_PATH__"
+	// nsexecCgroupPathKey is the cgroup the helper joins before it creates the namespace
+	nsexecCgroupPathKey string = "__CGROUP_PATH__"
+	// nsexecUserNSPathKey is the user namespace the helper joins before it creates or enters the namespace
+	nsexecUserNSPathKey string = "__USERNS_PATH__"
 )
 
 func NewNamespaceExecCreateHelper(key NamespaceFunctionKey, nsType types.NamespaceType, args map[string]string) (*NamespaceHelper, error) {
@@ -42,6 +47,16 @@ func NewNamespaceExecCreateHelper(key NamespaceFunctionKey, nsType types.Namespa
 }
 
 func NewNamespaceExecEnterHelper(key NamespaceFunctionKey, nsType types.NamespaceType, fdPath string, args map[string]string) (*NamespaceHelper, error) {
+	return newNamespaceExecPathHelper(nsexecOpEnter, key, nsType, fdPath, args)
+}
+
+// NewNamespaceExecCloneHelper returns a helper which runs the function in a copy of the namespace at fdPath,
+// the copy is kept until the helper is released
+func NewNamespaceExecCloneHelper(key NamespaceFunctionKey, nsType types.NamespaceType, fdPath string, args map[string]string) (*NamespaceHelper, error) {
+	return newNamespaceExecPathHelper(nsexecOpClone, key, nsType, fdPath, args)
+}
+
+func newNamespaceExecPathHelper(op string, key NamespaceFunctionKey, nsType types.NamespaceType, fdPath string, args map[string]string) (*NamespaceHelper, error) {
 	if fdPath == "" {
 		return nil, errors.New("empty namespace fd path")
 	}
@@ -52,7 +67,7 @@ func NewNamespaceExecEnterHelper(key NamespaceFunctionKey, nsType types.Namespac
 	cmd.Args = append(cmd.Args, string(key), string(nsType))
 	cmd.Env = append(
 		cmd.Env,
-		nsexecOpKey+"="+nsexecOpEnter,
+		nsexecOpKey+"="+op,
 		nsexecNSTypeKey+"="+string(nsType),
 		nsexecNSPathKey+"="+fdPath,
 	)
@@ -61,6 +76,20 @@ func NewNamespaceExecEnterHelper(key NamespaceFunctionKey, nsType types.Namespac
 	}, nil
 }
 
+// JoinCgroup lets a create helper join the cgroup of dir before it creates the namespace
+func (helper *NamespaceHelper) JoinCgroup(dir string) {
+	helper.Cmd.Env = append(helper.Cmd.Env, nsexecCgroupPathKey+"="+dir)
+}
+
+// JoinUserNamespace lets the helper join the user namespace at fdPath as its root before it creates, enters
+// or clones the namespace, so the namespace created is owned by it. It does nothing if fdPath is empty
+func (helper *NamespaceHelper) JoinUserNamespace(fdPath string) {
+	if fdPath == "" {
+		return
+	}
+	helper.Cmd.Env = append(helper.Cmd.Env, nsexecUserNSPathKey+"="+fdPath)
+}
+
 // Do executes the command
 func (helper *NamespaceHelper) Do(release bool) (err error) {
 	helper.stdin, err = helper.Cmd.StdinPipe()
@@ -0,0 +1,53 @@
+package namespace
+
+import (
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/YLonely/criuimages"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+// ReadImageEntry reads the first entry of the image file whose magic is magic into entry.
+// It is for the images whose magic is unknown to criuimages.New
+func ReadImageEntry(file string, magic uint32, entry proto.Message) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	head := make([]byte, 12)
+	if _, err = io.ReadFull(f, head); err != nil {
+		return errors.Wrapf(err, "failed to read the head of image %s", file)
+	}
+	if binary.LittleEndian.Uint32(head) != criuimages.IMG_COMMON_MAGIC || binary.LittleEndian.Uint32(head[4:]) != magic {
+		return errors.Errorf("unexpected magic of image %s", file)
+	}
+	data := make([]byte, binary.LittleEndian.Uint32(head[8:]))
+	if _, err = io.ReadFull(f, data); err != nil {
+		return errors.Wrapf(err, "failed to read the entry of image %s", file)
+	}
+	if err = proto.Unmarshal(data, entry); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal the entry of image %s", file)
+	}
+	return nil
+}
+
+// WriteImageEntry writes entry to the image file whose magic is magic, the file is truncated if it exists
+func WriteImageEntry(file string, magic uint32, entry proto.Message) error {
+	data, err := proto.Marshal(entry)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal entry")
+	}
+	head := make([]byte, 12)
+	binary.LittleEndian.PutUint32(head, criuimages.IMG_COMMON_MAGIC)
+	binary.LittleEndian.PutUint32(head[4:], magic)
+	binary.LittleEndian.PutUint32(head[8:], uint32(len(data)))
+	if err = ioutil.WriteFile(file, append(head, data...), 0644); err != nil {
+		return errors.Wrapf(err, "failed to write image %s", file)
+	}
+	return nil
+}
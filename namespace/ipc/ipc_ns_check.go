@@ -0,0 +1,153 @@
+package ipc
+
+import (
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"github.com/YLonely/criuimages"
+	criutype "github.com/YLonely/criuimages/types"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+// checkNamespace runs in an idle IPC namespace, it checks the number of the SysV objects against the checkpoints
+func checkNamespace(args map[string]interface{}) ([]byte, error) {
+	cp, ok := args["checkpoint"].(string)
+	if !ok || cp == "" {
+		return nil, errors.New("checkpoint must be provided")
+	}
+	counts := map[string]int{}
+	for _, c := range append(mergedCheckpoints(args), cp) {
+		n, _, err := checkpointObjects(c)
+		if err != nil {
+			return nil, err
+		}
+		for kind := range n {
+			counts[kind] += n[kind]
+		}
+	}
+	for _, obj := range sysvObjects {
+		ids, err := sysvIDs(obj.name)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) != counts[obj.name] {
+			return nil, errors.Errorf("%d %s exist instead of %d", len(ids), obj.name, counts[obj.name])
+		}
+	}
+	return nil, nil
+}
+
+// checkpointObjects returns the number of the SysV objects of each kind in the checkpoint
+// and the total size of the shm segments
+func checkpointObjects(checkpoint string) (map[string]int, int64, error) {
+	descs, shmBytes, err := checkpointDescs(checkpoint)
+	if err != nil {
+		return nil, 0, err
+	}
+	counts := map[string]int{}
+	for kind := range descs {
+		counts[kind] = len(descs[kind])
+	}
+	return counts, shmBytes, nil
+}
+
+// checkpointDescs returns the descs of the SysV objects of each kind in the checkpoint
+// and the total size of the shm segments
+func checkpointDescs(checkpoint string) (map[string][]*criutype.IpcDescEntry, int64, error) {
+	infos, err := ioutil.ReadDir(checkpoint)
+	if err != nil {
+		return nil, 0, err
+	}
+	descs := map[string][]*criutype.IpcDescEntry{}
+	var shmBytes int64
+	for _, info := range infos {
+		var kind string
+		for _, k := range []string{"shm", "msg", "sem"} {
+			if strings.HasPrefix(info.Name(), "ipcns-"+k+"-") {
+				kind = k
+			}
+		}
+		if kind == "" {
+			continue
+		}
+		d, size, err := readDescs(path.Join(checkpoint, info.Name()), kind)
+		if err != nil {
+			return nil, 0, errors.Wrap(err, "failed to read image "+info.Name())
+		}
+		descs[kind] = append(descs[kind], d...)
+		shmBytes += size
+	}
+	return descs, shmBytes, nil
+}
+
+// readDescs reads the descs of the objects of kind in the image file, skipping the data following each of them
+// the same way they are restored
+func readDescs(file, kind string) ([]*criutype.IpcDescEntry, int64, error) {
+	img, err := criuimages.New(file)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer img.Close()
+	var descs []*criutype.IpcDescEntry
+	var size int64
+	for {
+		var entry interface {
+			proto.Message
+			GetDesc() *criutype.IpcDescEntry
+		}
+		switch kind {
+		case "shm":
+			entry = &criutype.IpcShmEntry{}
+		case "msg":
+			entry = &criutype.IpcMsgEntry{}
+		default:
+			entry = &criutype.IpcSemEntry{}
+		}
+		if err = img.ReadOne(entry); err != nil {
+			if err == io.EOF {
+				return descs, size, nil
+			}
+			return nil, 0, err
+		}
+		if err = skipObjectData(img, entry); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, 0, err
+		}
+		if shm, ok := entry.(*criutype.IpcShmEntry); ok {
+			size += int64(shm.GetSize())
+		}
+		descs = append(descs, entry.GetDesc())
+	}
+}
+
+// skipObjectData skips the data following entry in img
+func skipObjectData(img *criuimages.Image, entry proto.Message) error {
+	skip := func(n uint64) error {
+		_, err := io.CopyN(ioutil.Discard, img.File(), int64(n))
+		return err
+	}
+	switch e := entry.(type) {
+	case *criutype.IpcShmEntry:
+		if !e.GetInPagemaps() {
+			return skip(roundUp(e.GetSize(), 4))
+		}
+	case *criutype.IpcMsgEntry:
+		for i := 0; i < int(e.GetQnum()); i++ {
+			msg := &criutype.IpcMsg{}
+			if err := img.ReadOne(msg); err != nil {
+				return err
+			}
+			if err := skip(roundUp(uint64(msg.GetMsize()), 8)); err != nil {
+				return err
+			}
+		}
+	case *criutype.IpcSemEntry:
+		return skip(roundUp(uint64(2*e.GetNsems()), 8))
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package ipc
+
+import (
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	apiservices "github.com/YLonely/cer-manager/api/services"
+	"github.com/YLonely/cer-manager/api/types"
+	"github.com/YLonely/cer-manager/log"
+	"github.com/YLonely/cer-manager/namespace"
+	"github.com/YLonely/criuimages"
+	criutype "github.com/YLonely/criuimages/types"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// mergedDir is the dir under the root of the manager holding the vars of the merged namespaces
+	mergedDir = "ipc"
+	// mergedLabel is the label of a merged reference listing the digests of the references merged
+	mergedLabel = "merged"
+	// ipcMNI is IPCMNI in <linux/ipc.h>, an object restored takes the slot of its id modulo it.
+	// The slots are larger with ipcmni_extend, so the check is stricter than needed then
+	ipcMNI = 1 << 15
+)
+
+// mergedRef returns the reference of the namespaces merging the IPC objects of refs,
+// it is the same whatever the order of refs is
+func mergedRef(refs []types.Reference) types.Reference {
+	sorted := append([]types.Reference{}, refs...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Digest() < sorted[j].Digest() })
+	names := make([]string, 0, len(sorted))
+	digests := make([]string, 0, len(sorted))
+	for _, ref := range sorted {
+		names = append(names, ref.Name)
+		digests = append(digests, ref.Digest())
+	}
+	return types.Reference{
+		Name:   strings.Join(names, "+"),
+		Labels: map[string]string{mergedLabel: strings.Join(digests, ",")},
+	}
+}
+
+// initMergedSet creates the pool of the namespaces merging the IPC objects of refs. The pool is as large
+// as the smallest one of refs and is filled by the refiller, it is not autoscaled
+func (m *manager) initMergedSet(target types.Reference, refs []types.Reference) error {
+	owner := m.owners.Path(refs[0], types.NamespaceIPC)
+	s := ipcSet{}
+	capacity := 0
+	for i, ref := range refs {
+		if m.owners.Path(ref, types.NamespaceIPC) != owner {
+			return apiservices.Errorf(apiservices.CodeInvalidArgument, "IPC namespaces of %s and %s are owned by different user namespaces", refs[0], ref)
+		}
+		s.checkpoints = append(s.checkpoints, m.sets[ref.Digest()].checkpoint)
+		if target := m.SetOf(ref).Target(); i == 0 || target < capacity {
+			capacity = target
+		}
+	}
+	vars, shmBytes, err := mergeCheckpoints(m.ipcDefaultVars, s.checkpoints)
+	if err != nil {
+		return apiservices.Errorf(apiservices.CodeInvalidArgument, "IPC objects of %v can not be merged: %v", refs, err)
+	}
+	s.checkpoint = path.Join(m.root, mergedDir, target.Digest())
+	if err = os.MkdirAll(s.checkpoint, 0755); err != nil {
+		return errors.Wrap(err, "failed to create the dir of the merged vars")
+	}
+	if err = namespace.WriteImageEntry(path.Join(s.checkpoint, dumpFileNamePrefixes[3]+"merged.img"), criuimages.IPC_VAR_MAGIC, vars); err != nil {
+		return err
+	}
+	p := s.pool(0, shmBytes, owner)
+	p.Members = refs
+	if err = m.Add(target, p); err != nil {
+		return err
+	}
+	m.sets[target.Digest()] = s
+	log.Raw().Infof("IPC objects of %v are merged into %s", refs, target)
+	m.SetOf(target).SetTarget(capacity)
+	return nil
+}
+
+// mergeCheckpoints checks that the SysV objects of the checkpoints can live in one namespace and that
+// the vars they change agree. It returns the merged vars and the total size of the shm segments
+func mergeCheckpoints(defaults *criutype.IpcVarEntry, checkpoints []string) (*criutype.IpcVarEntry, int64, error) {
+	merged := proto.Clone(defaults).(*criutype.IpcVarEntry)
+	// changed maps a var to the checkpoint changing it
+	changed := map[protoreflect.Name]string{}
+	// slots and keys map the slot and the key of an object of a kind to the checkpoint holding it
+	slots := map[string]map[uint32]string{}
+	keys := map[string]map[uint32]string{}
+	var shmBytes int64
+	for _, cp := range checkpoints {
+		descs, size, err := checkpointDescs(cp)
+		if err != nil {
+			return nil, 0, err
+		}
+		shmBytes += size
+		for kind, ds := range descs {
+			if slots[kind] == nil {
+				slots[kind], keys[kind] = map[uint32]string{}, map[uint32]string{}
+			}
+			for _, desc := range ds {
+				slot := desc.GetId() % ipcMNI
+				if other, exists := slots[kind][slot]; exists {
+					return nil, 0, errors.Errorf("%s slot %d is taken in both %s and %s", kind, slot, other, cp)
+				}
+				slots[kind][slot] = cp
+				// the private objects share the key 0
+				if desc.GetKey() == 0 {
+					continue
+				}
+				if other, exists := keys[kind][desc.GetKey()]; exists {
+					return nil, 0, errors.Errorf("%s key %#x is in both %s and %s", kind, desc.GetKey(), other, cp)
+				}
+				keys[kind][desc.GetKey()] = cp
+			}
+		}
+		vars, err := checkpointVars(cp)
+		if err != nil {
+			return nil, 0, err
+		}
+		fields := vars.ProtoReflect().Descriptor().Fields()
+		for i := 0; i < fields.Len(); i++ {
+			fd := fields.Get(i)
+			if !vars.ProtoReflect().Has(fd) {
+				continue
+			}
+			v := varOf(vars, fd)
+			if proto.Equal(v, varOf(defaults, fd)) {
+				continue
+			}
+			if other, exists := changed[fd.Name()]; exists {
+				if !proto.Equal(v, varOf(merged, fd)) {
+					return nil, 0, errors.Errorf("var %s differs between %s and %s", fd.Name(), other, cp)
+				}
+				continue
+			}
+			changed[fd.Name()] = cp
+			merged.ProtoReflect().Set(fd, vars.ProtoReflect().Get(fd))
+		}
+	}
+	return merged, shmBytes, nil
+}
+
+// varOf returns an entry holding only the var fd of entry, so the vars can be compared one by one
+func varOf(entry *criutype.IpcVarEntry, fd protoreflect.FieldDescriptor) *criutype.IpcVarEntry {
+	ret := &criutype.IpcVarEntry{}
+	if entry.ProtoReflect().Has(fd) {
+		ret.ProtoReflect().Set(fd, entry.ProtoReflect().Get(fd))
+	}
+	return ret
+}
@@ -0,0 +1,206 @@
+package ipc
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/YLonely/cer-manager/namespace"
+	"github.com/YLonely/criuimages"
+	criutype "github.com/YLonely/criuimages/types"
+	"google.golang.org/protobuf/proto"
+)
+
+func testVars() *criutype.IpcVarEntry {
+	return &criutype.IpcVarEntry{
+		SemCtls:       []uint32{32000, 1024000000, 500, 32000},
+		MsgCtlmax:     proto.Uint32(8192),
+		MsgCtlmnb:     proto.Uint32(16384),
+		MsgCtlmni:     proto.Uint32(32000),
+		AutoMsgmni:    proto.Uint32(0),
+		ShmCtlmax:     proto.Uint64(1 << 40),
+		ShmCtlall:     proto.Uint64(1 << 30),
+		ShmCtlmni:     proto.Uint32(4096),
+		ShmRmidForced: proto.Uint32(0),
+		MqQueuesMax:   proto.Uint32(256),
+		MqMsgMax:      proto.Uint32(10),
+		MqMsgsizeMax:  proto.Uint32(8192),
+	}
+}
+
+// testObject is a SysV object in a checkpoint, only shm segments have a size
+type testObject struct {
+	kind string
+	id   uint32
+	key  uint32
+	size uint64
+}
+
+// writeCheckpoint writes the IPC images of a checkpoint holding objects, change modifies the vars of the defaults
+func writeCheckpoint(t *testing.T, objects []testObject, change func(*criutype.IpcVarEntry)) string {
+	dir, err := ioutil.TempDir("", "ipc-merge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	vars := testVars()
+	if change != nil {
+		change(vars)
+	}
+	if err = namespace.WriteImageEntry(path.Join(dir, dumpFileNamePrefixes[3]+"11.img"), criuimages.IPC_VAR_MAGIC, vars); err != nil {
+		t.Fatal(err)
+	}
+	magics := map[string]uint32{
+		"shm": criuimages.IPCNS_SHM_MAGIC,
+		"msg": criuimages.IPCNS_MSG_MAGIC,
+		"sem": criuimages.IPCNS_SEM_MAGIC,
+	}
+	images := map[string][]byte{}
+	for _, o := range objects {
+		desc := &criutype.IpcDescEntry{
+			Key:  proto.Uint32(o.key),
+			Uid:  proto.Uint32(0),
+			Gid:  proto.Uint32(0),
+			Cuid: proto.Uint32(0),
+			Cgid: proto.Uint32(0),
+			Mode: proto.Uint32(0600),
+			Id:   proto.Uint32(o.id),
+		}
+		var entry proto.Message
+		switch o.kind {
+		case "shm":
+			// the pages are in the pagemaps, so no data follows
+			entry = &criutype.IpcShmEntry{Desc: desc, Size: proto.Uint64(o.size), InPagemaps: proto.Bool(true)}
+		case "msg":
+			entry = &criutype.IpcMsgEntry{Desc: desc, Qbytes: proto.Uint32(16384), Qnum: proto.Uint32(0)}
+		default:
+			entry = &criutype.IpcSemEntry{Desc: desc, Nsems: proto.Uint32(0)}
+		}
+		data, err := proto.Marshal(entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if images[o.kind] == nil {
+			head := make([]byte, 8)
+			binary.LittleEndian.PutUint32(head, criuimages.IMG_COMMON_MAGIC)
+			binary.LittleEndian.PutUint32(head[4:], magics[o.kind])
+			images[o.kind] = head
+		}
+		size := make([]byte, 4)
+		binary.LittleEndian.PutUint32(size, uint32(len(data)))
+		images[o.kind] = append(images[o.kind], append(size, data...)...)
+	}
+	for kind, img := range images {
+		if err = ioutil.WriteFile(path.Join(dir, "ipcns-"+kind+"-11.img"), img, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestMergeCheckpoints(t *testing.T) {
+	shmMax := func(v uint64) func(*criutype.IpcVarEntry) {
+		return func(e *criutype.IpcVarEntry) { e.ShmCtlmax = proto.Uint64(v) }
+	}
+	tests := []struct {
+		name        string
+		checkpoints [][]testObject
+		changes     []func(*criutype.IpcVarEntry)
+		// err is a part of the error expected, empty if the checkpoints can be merged
+		err      string
+		shmBytes int64
+		shmMax   uint64
+	}{
+		{
+			name: "disjoint objects",
+			checkpoints: [][]testObject{
+				{{kind: "shm", id: 1, key: 0x10, size: 4096}, {kind: "sem", id: 1, key: 0x10}},
+				{{kind: "shm", id: 2, key: 0x20, size: 8192}, {kind: "msg", id: 1, key: 0x20}},
+			},
+			shmBytes: 12288,
+			shmMax:   1 << 40,
+		},
+		{
+			name: "private objects share key 0",
+			checkpoints: [][]testObject{
+				{{kind: "shm", id: 1, key: 0, size: 4096}},
+				{{kind: "shm", id: 2, key: 0, size: 4096}},
+			},
+			shmBytes: 8192,
+			shmMax:   1 << 40,
+		},
+		{
+			name: "same slot",
+			checkpoints: [][]testObject{
+				{{kind: "shm", id: 1, key: 0x10}},
+				{{kind: "shm", id: 1 + ipcMNI, key: 0x20}},
+			},
+			err: "shm slot 1 is taken",
+		},
+		{
+			name: "same slot of different kinds",
+			checkpoints: [][]testObject{
+				{{kind: "shm", id: 1, key: 0x10}},
+				{{kind: "msg", id: 1, key: 0x10}},
+			},
+			shmMax: 1 << 40,
+		},
+		{
+			name: "same key",
+			checkpoints: [][]testObject{
+				{{kind: "sem", id: 1, key: 0x10}},
+				{{kind: "sem", id: 2, key: 0x10}},
+			},
+			err: "sem key 0x10 is in both",
+		},
+		{
+			name:        "same var changed alike",
+			checkpoints: [][]testObject{nil, nil},
+			changes:     []func(*criutype.IpcVarEntry){shmMax(1 << 20), shmMax(1 << 20)},
+			shmMax:      1 << 20,
+		},
+		{
+			name:        "var changed by one",
+			checkpoints: [][]testObject{nil, nil},
+			changes:     []func(*criutype.IpcVarEntry){nil, shmMax(1 << 20)},
+			shmMax:      1 << 20,
+		},
+		{
+			name:        "var changed differently",
+			checkpoints: [][]testObject{nil, nil},
+			changes:     []func(*criutype.IpcVarEntry){shmMax(1 << 20), shmMax(1 << 21)},
+			err:         "var shm_ctlmax differs",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var checkpoints []string
+			for i, objects := range tt.checkpoints {
+				var change func(*criutype.IpcVarEntry)
+				if i < len(tt.changes) {
+					change = tt.changes[i]
+				}
+				checkpoints = append(checkpoints, writeCheckpoint(t, objects, change))
+			}
+			vars, shmBytes, err := mergeCheckpoints(testVars(), checkpoints)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("mergeCheckpoints() returned %v, want an error with %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if shmBytes != tt.shmBytes {
+				t.Errorf("the shm segments take %d bytes, want %d", shmBytes, tt.shmBytes)
+			}
+			if vars.GetShmCtlmax() != tt.shmMax {
+				t.Errorf("merged shm_ctlmax is %d, want %d", vars.GetShmCtlmax(), tt.shmMax)
+			}
+		})
+	}
+}
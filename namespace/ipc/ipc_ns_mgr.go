@@ -8,10 +8,12 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
 
+	apiservices "github.com/YLonely/cer-manager/api/services"
 	"github.com/YLonely/cer-manager/api/types"
 	"github.com/YLonely/cer-manager/log"
 	"github.com/YLonely/cer-manager/namespace"
@@ -26,26 +28,36 @@ import (
 func init() {
 	namespace.PutNamespaceFunction(functionKeyCollect, types.NamespaceIPC, collect)
 	namespace.PutNamespaceFunction(namespace.NamespaceFunctionKeyCreate, types.NamespaceIPC, populateNamespace)
+	namespace.PutNamespaceFunction(namespace.NamespaceFunctionKeyReset, types.NamespaceIPC, resetNamespace)
+	namespace.PutNamespaceFunction(namespace.NamespaceFunctionKeyCheck, types.NamespaceIPC, checkNamespace)
 }
 
-// NewManager returns a new ipc namespace manager
-func NewManager(root string, capacities []int, refs []types.Reference, supplier types.Supplier) (namespace.Manager, error) {
+// NewManager returns a new ipc namespace manager, the namespaces of the references in owners
+// are created in their owner user namespaces. If merge is true, the IPC objects of the references
+// got together are merged into one namespace instead of colliding
+func NewManager(root string, capacities []int, refs []types.Reference, supplier types.Supplier, refiller *namespace.Refiller, budget *namespace.Budget, owners *namespace.Owners, merge bool) (namespace.Manager, error) {
 	defaultVars, err := getDefaultNamespace()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to collect varaibles from new ipc namespace")
 	}
 	ret := &manager{
-		supplier: supplier,
-		sets: map[string]struct {
-			ipcContentNormal bool
-			set              *namespace.Set
-		}{},
-		usedNamespace: map[int]struct {
-			ref types.Reference
-			f   *os.File
-		}{},
+		root:           root,
+		merge:          merge,
+		supplier:       supplier,
+		sets:           map[string]ipcSet{},
+		owners:         owners,
 		ipcDefaultVars: defaultVars,
 	}
+	pools, err := namespace.NewPoolManager(namespace.PoolConfig{
+		Type:     types.NamespaceIPC,
+		Select:   ret.targetRef,
+		Refiller: refiller,
+		Budget:   budget,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret.PoolManager = pools
 	for i, ref := range refs {
 		if err := ret.initSet(ref, capacities[i]); err != nil {
 			return nil, err
@@ -61,104 +73,103 @@ const (
 )
 
 type manager struct {
-	sets map[string]struct {
-		ipcContentNormal bool
-		set              *namespace.Set
-	}
-	supplier types.Supplier
-	mu       sync.Mutex
-	// usedNamespace maps a fd to the file it belongs
-	usedNamespace map[int]struct {
-		ref types.Reference
-		f   *os.File
-	}
+	*namespace.PoolManager
+	root string
+	// merge enables the merged namespaces of the references whose IPC objects collide
+	merge bool
+	// sets maps the digest of a reference, or of the merged reference of several ones, to how its namespaces are restored
+	sets           map[string]ipcSet
+	supplier       types.Supplier
+	mu             sync.Mutex
+	owners         *namespace.Owners
 	ipcDefaultVars *criutype.IpcVarEntry
 }
 
-func (m *manager) Get(ref types.Reference, extraRefs ...types.Reference) (fd int, info interface{}, err error) {
-	var target types.Reference
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	target, err = m.targetRef(ref, extraRefs...)
-	if err != nil {
-		return
-	}
-	set, exists := m.sets[target.Digest()]
-	if !exists {
-		err = errors.Errorf("IPC namespace of %s does not exist", target)
-		return
+type ipcSet struct {
+	ipcContentNormal bool
+	// checkpoint is the path of the checkpoint the namespaces are restored from
+	checkpoint string
+	// checkpoints are the checkpoints whose objects are merged into the namespaces,
+	// checkpoint only holds the merged vars if there are any
+	checkpoints []string
+}
+
+// args returns the args of the helpers restoring the namespaces of s
+func (s ipcSet) args() map[string]string {
+	args := map[string]string{
+		"checkpoint": s.checkpoint,
 	}
-	f := set.set.Get()
-	if f == nil {
-		err = errors.Errorf("IPC namespace of %s is used up")
-		return
+	if len(s.checkpoints) != 0 {
+		args["checkpoints"] = strings.Join(s.checkpoints, string(filepath.ListSeparator))
 	}
-	go func() {
-		m.mu.Lock()
-		defer m.mu.Unlock()
-		if err := set.set.CreateOne(); err != nil {
-			log.Raw().WithError(err).Errorf("failed to create a new IPC namespace for %s", ref)
-		}
-	}()
-	fd = int(f.Fd())
-	m.usedNamespace[fd] = struct {
-		ref types.Reference
-		f   *os.File
-	}{
-		ref: target,
-		f:   f,
+	return args
+}
+
+// reset enters the namespace f, clears all the objects in it and restores the ones of the checkpoint
+func (s ipcSet) reset(f *os.File) error {
+	return s.enter(namespace.NamespaceFunctionKeyReset, f)
+}
+
+// check enters the namespace f and checks that it holds the objects of the checkpoint
+func (s ipcSet) check(f *os.File) error {
+	return s.enter(namespace.NamespaceFunctionKeyCheck, f)
+}
+
+// enter runs the function of key in the namespace f
+func (s ipcSet) enter(key namespace.NamespaceFunctionKey, f *os.File) error {
+	h, err := namespace.NewNamespaceExecEnterHelper(
+		key,
+		types.NamespaceIPC,
+		fmt.Sprintf("/proc/%d/fd/%d", os.Getpid(), int(f.Fd())),
+		s.args(),
+	)
+	if err != nil {
+		return err
 	}
-	return
+	return h.Do(true)
 }
 
-func (m *manager) Put(fd int) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	item, exists := m.usedNamespace[fd]
-	if !exists {
-		return errors.Errorf("invalid fd %d", fd)
+// pool returns the pool of the namespaces of s, they are created in the user namespace owner
+func (s ipcSet) pool(capacity int, shmBytes int64, owner string) namespace.Pool {
+	return namespace.Pool{
+		Capacity: capacity,
+		Cost:     types.Resources{Namespaces: 1, ShmBytes: shmBytes},
+		Create:   makeIPCNamespaceCreator(s.args(), owner),
+		Check:    s.check,
+		// restoring a namespace from the checkpoint is costly, so reset it and put it back to the set
+		Put:   namespace.PutRecycle,
+		Reset: s.reset,
 	}
-	item.f.Close()
-	delete(m.usedNamespace, fd)
-	return nil
 }
 
+// Update resizes the pool of ref, the pool is created if ref is not pooled yet
 func (m *manager) Update(ref types.Reference, capacity int) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	set, exists := m.sets[ref.Digest()]
-	if !exists {
-		if err := m.initSet(ref, capacity); err != nil {
-			return err
-		}
-		return nil
+	if _, exists := m.sets[ref.Digest()]; !exists {
+		return m.initSet(ref, capacity)
 	}
-	return set.set.Update(capacity)
+	return m.PoolManager.Update(ref, capacity)
 }
 
 func (m *manager) CleanUp() error {
-	var last error
-	for _, item := range m.usedNamespace {
-		item.f.Close()
-		log.Raw().Warnf("IPC namespace %d of %s is being used", item.f.Fd(), item.ref)
-	}
-	for _, set := range m.sets {
-		if err := set.set.CleanUp(); err != nil {
-			last = err
-			log.Raw().Error(err)
-		}
+	last := m.PoolManager.CleanUp()
+	if err := os.RemoveAll(path.Join(m.root, mergedDir)); err != nil {
+		last = err
+		log.Raw().WithError(err).Error("failed to remove the vars of the merged IPC namespaces")
 	}
 	return last
 }
 
+// initSet creates the pool of ref, the caller holds the lock unless the manager is being created
 func (m *manager) initSet(ref types.Reference, capacity int) error {
 	cp, err := m.supplier.Get(ref)
 	if err != nil {
 		return errors.Wrapf(err, "failed to get checkpoint path for %s", ref)
 	}
-	set, err := namespace.NewSet(capacity, makeIPCNamespaceCreator(cp), func(f *os.File) error { return nil })
+	_, shmBytes, err := checkpointObjects(cp)
 	if err != nil {
-		return err
+		return errors.Wrapf(err, "failed to get the size of shm of %s", ref)
 	}
 	contentNormal, err := inDefaultNamespace(m.ipcDefaultVars, cp)
 	if err != nil {
@@ -167,52 +178,56 @@ func (m *manager) initSet(ref types.Reference, capacity int) error {
 	if !contentNormal {
 		log.Raw().Infof("IPC namespace of %s contains extra data", ref)
 	}
-	m.sets[ref.Digest()] = struct {
-		ipcContentNormal bool
-		set              *namespace.Set
-	}{
-		ipcContentNormal: contentNormal,
-		set:              set,
+	s := ipcSet{checkpoint: cp, ipcContentNormal: contentNormal}
+	if err = m.Add(ref, s.pool(capacity, shmBytes, m.owners.Path(ref, types.NamespaceIPC))); err != nil {
+		return err
 	}
+	m.sets[ref.Digest()] = s
 	return nil
 }
 
-func (m *manager) targetRef(ref types.Reference, extraRefs ...types.Reference) (types.Reference, error) {
-	if len(extraRefs) == 0 {
-		return ref, nil
-	}
-	refs := append(extraRefs, ref)
-	var target *types.Reference
-	for _, ref := range refs {
-		if set, exists := m.sets[ref.Digest()]; exists {
-			if !set.ipcContentNormal {
-				if target == nil {
-					target = &ref
-				} else {
-					return types.Reference{}, errors.Errorf("namespace collision among references %v", refs)
-				}
-			}
+// targetRef returns the reference whose namespace is got for refs and why it is picked,
+// it is a merged reference if several of them have IPC objects besides the defaults
+func (m *manager) targetRef(refs []types.Reference) (types.Reference, *os.File, string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var dirty []types.Reference
+	seen := map[string]bool{}
+	for _, r := range refs {
+		if set, exists := m.sets[r.Digest()]; exists && !set.ipcContentNormal && !seen[r.Digest()] {
+			seen[r.Digest()] = true
+			dirty = append(dirty, r)
 		}
 	}
-	if target == nil {
-		target = &ref
+	switch {
+	case len(dirty) == 0:
+		return refs[0], nil, "no reference has IPC objects besides the defaults", nil
+	case len(dirty) == 1:
+		return dirty[0], nil, fmt.Sprintf("%s is the only reference with IPC objects besides the defaults", dirty[0]), nil
+	case !m.merge:
+		return types.Reference{}, nil, "", apiservices.Errorf(apiservices.CodeInvalidArgument, "namespace collision among references %v", refs)
 	}
-	return *target, nil
+	target := mergedRef(dirty)
+	if _, exists := m.sets[target.Digest()]; !exists {
+		if err := m.initMergedSet(target, dirty); err != nil {
+			return types.Reference{}, nil, "", err
+		}
+	}
+	return target, nil, fmt.Sprintf("the IPC objects of %v are merged", dirty), nil
 }
 
-func makeIPCNamespaceCreator(checkpointPath string) func() (*os.File, error) {
+func makeIPCNamespaceCreator(args map[string]string, owner string) func() (*os.File, error) {
 	return func() (f *os.File, err error) {
 		var h *namespace.NamespaceHelper
 		h, err = namespace.NewNamespaceExecCreateHelper(
 			namespace.NamespaceFunctionKeyCreate,
 			types.NamespaceIPC,
-			map[string]string{
-				"checkpoint": checkpointPath,
-			},
+			args,
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to create ipc create helper")
 		}
+		h.JoinUserNamespace(owner)
 		if err = h.Do(false); err != nil {
 			return
 		}
@@ -225,17 +240,42 @@ func makeIPCNamespaceCreator(checkpointPath string) func() (*os.File, error) {
 	}
 }
 
+// populateNamespace restores the objects of each of the merged checkpoints in turn,
+// then the objects and the vars of the checkpoint
 func populateNamespace(args map[string]interface{}) ([]byte, error) {
 	cp, ok := args["checkpoint"].(string)
 	if !ok || cp == "" {
 		return nil, errors.New("checkpoint must be provided")
 	}
-	if err := os.Chdir(cp); err != nil {
+	for _, c := range mergedCheckpoints(args) {
+		if err := restoreCheckpoint(c, false); err != nil {
+			return nil, err
+		}
+	}
+	if err := restoreCheckpoint(cp, true); err != nil {
 		return nil, err
 	}
+	return nil, nil
+}
+
+// mergedCheckpoints returns the checkpoints whose objects are merged into the namespace,
+// the checkpoint of a merged namespace only holds the merged vars
+func mergedCheckpoints(args map[string]interface{}) []string {
+	cps, _ := args["checkpoints"].(string)
+	if cps == "" {
+		return nil
+	}
+	return filepath.SplitList(cps)
+}
+
+// restoreCheckpoint restores the objects in the checkpoint cp, and its vars if vars is true
+func restoreCheckpoint(cp string, vars bool) error {
+	if err := os.Chdir(cp); err != nil {
+		return err
+	}
 	infos, err := ioutil.ReadDir(".")
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to read dir "+cp)
+		return errors.Wrap(err, "failed to read dir "+cp)
 	}
 	const (
 		varFilePrefix = "ipcns-var-"
@@ -249,26 +289,29 @@ func populateNamespace(args map[string]interface{}) ([]byte, error) {
 			pre := info.Name()[:prefixLen]
 			switch pre {
 			case varFilePrefix:
+				if !vars {
+					continue
+				}
 				if err = restoreIPCVars(info.Name()); err != nil {
-					return nil, errors.Wrap(err, "failed to restore vars using "+info.Name())
+					return errors.Wrap(err, "failed to restore vars using "+info.Name())
 				}
 			case shmFilePrefix:
 				if err = restoreIPCShm(info.Name()); err != nil {
-					return nil, errors.Wrap(err, "failed to restore shm using "+info.Name())
+					return errors.Wrap(err, "failed to restore shm using "+info.Name())
 				}
 			case msgFilePrefix:
 				if err = restoreIPCMsg(info.Name()); err != nil {
-					return nil, errors.Wrap(err, "failed to restore msg using "+info.Name())
+					return errors.Wrap(err, "failed to restore msg using "+info.Name())
 				}
 			case semFilePrefix:
 				if err = restoreIPCSem(info.Name()); err != nil {
-					return nil, errors.Wrap(err, "failed to restore sem using "+info.Name())
+					return errors.Wrap(err, "failed to restore sem using "+info.Name())
 				}
 			default:
 			}
 		}
 	}
-	return nil, nil
+	return nil
 }
 
 func restoreIPCSem(file string) error {
@@ -506,8 +549,6 @@ func restoreFromPagemaps(shmid int, shm *ipcgo.SharedMemory) error {
 
 func inDefaultNamespace(vars *criutype.IpcVarEntry, cp string) (bool, error) {
 	extraFilePrefixes := dumpFileNamePrefixes[:3]
-	varsFilePrefix := dumpFileNamePrefixes[3]
-	var varsFileName string
 	infos, err := ioutil.ReadDir(cp)
 	if err != nil {
 		return false, errors.Wrap(err, "failed to read dir "+cp)
@@ -518,24 +559,41 @@ func inDefaultNamespace(vars *criutype.IpcVarEntry, cp string) (bool, error) {
 				return false, nil
 			}
 		}
+	}
+	entry, err := checkpointVars(cp)
+	if err != nil {
+		return false, err
+	}
+	return proto.Equal(entry, vars), nil
+}
+
+// checkpointVars returns the entry in the vars image of the checkpoint
+func checkpointVars(cp string) (*criutype.IpcVarEntry, error) {
+	varsFilePrefix := dumpFileNamePrefixes[3]
+	var varsFileName string
+	infos, err := ioutil.ReadDir(cp)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read dir "+cp)
+	}
+	for _, info := range infos {
 		if strings.HasPrefix(info.Name(), varsFilePrefix) {
 			varsFileName = info.Name()
 		}
 	}
 	if varsFileName == "" {
-		return false, errors.Errorf("file with prefix %s does not exist", varsFilePrefix)
+		return nil, errors.Errorf("file with prefix %s does not exist", varsFilePrefix)
 	}
 	imgPath := path.Join(cp, varsFileName)
 	img, err := criuimages.New(imgPath)
 	if err != nil {
-		return false, errors.Wrap(err, "failed to create image")
+		return nil, errors.Wrap(err, "failed to create image")
 	}
 	defer img.Close()
 	entry := &criutype.IpcVarEntry{}
 	if err = img.ReadOne(entry); err != nil {
-		return false, errors.Wrap(err, "failed to read entry")
+		return nil, errors.Wrap(err, "failed to read entry")
 	}
-	return proto.Equal(entry, vars), nil
+	return entry, nil
 }
 
 func getDefaultNamespace() (*criutype.IpcVarEntry, error) {
@@ -0,0 +1,147 @@
+package ipc
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// ipcRMID is IPC_RMID in <linux/ipc.h>
+const ipcRMID = 0
+
+// sysvObjects are the SysV objects in /proc/sysvipc and the syscalls removing them
+var sysvObjects = []struct {
+	name   string
+	remove func(id int) error
+}{
+	{
+		name: "shm",
+		remove: func(id int) error {
+			return ipcctl(unix.SYS_SHMCTL, id, ipcRMID, 0)
+		},
+	},
+	{
+		name: "msg",
+		remove: func(id int) error {
+			return ipcctl(unix.SYS_MSGCTL, id, ipcRMID, 0)
+		},
+	},
+	{
+		name: "sem",
+		remove: func(id int) error {
+			return ipcctl(unix.SYS_SEMCTL, id, 0, ipcRMID)
+		},
+	},
+}
+
+func ipcctl(trap uintptr, id int, a1, a2 uintptr) error {
+	if _, _, errno := unix.Syscall(trap, uintptr(id), a1, a2); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// resetNamespace runs in the IPC namespace put back, it removes all the objects left by the borrower
+// and restores the objects and the vars of the checkpoint
+func resetNamespace(args map[string]interface{}) ([]byte, error) {
+	if err := clearSysV(); err != nil {
+		return nil, err
+	}
+	if err := clearPosixQueues(); err != nil {
+		return nil, err
+	}
+	return populateNamespace(args)
+}
+
+// clearSysV removes all the SysV objects of the namespace and checks that none of them survives
+func clearSysV() error {
+	for _, obj := range sysvObjects {
+		ids, err := sysvIDs(obj.name)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if err := obj.remove(id); err != nil && err != unix.EINVAL && err != unix.EIDRM {
+				return errors.Wrapf(err, "failed to remove %s %d", obj.name, id)
+			}
+		}
+		// a removed shm is only marked as destroyed until the processes attached to it detach
+		if ids, err = sysvIDs(obj.name); err != nil {
+			return err
+		}
+		if len(ids) != 0 {
+			return errors.Errorf("%s %v still exist after removal", obj.name, ids)
+		}
+	}
+	return nil
+}
+
+// sysvIDs returns the ids of the SysV objects of kind in the namespace we are in
+func sysvIDs(kind string) ([]int, error) {
+	f, err := os.Open(path.Join("/proc/sysvipc", kind))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var ids []int
+	scanner := bufio.NewScanner(f)
+	// skip the header
+	scanner.Scan()
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		id, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid line %q of /proc/sysvipc/%s", scanner.Text(), kind)
+		}
+		ids = append(ids, id)
+	}
+	return ids, scanner.Err()
+}
+
+// clearPosixQueues removes all the POSIX message queues of the namespace. The queues are only visible
+// through a mqueue fs mounted in the namespace, so the fs is mounted in a private mount namespace of this thread
+func clearPosixQueues() error {
+	runtime.LockOSThread()
+	// the thread is left locked, it is dirty with the private mount namespace
+	if err := unix.Unshare(unix.CLONE_NEWNS); err != nil {
+		return errors.Wrap(err, "failed to unshare mount namespace")
+	}
+	if err := unix.Mount("", "/", "", unix.MS_REC|unix.MS_PRIVATE, ""); err != nil {
+		return errors.Wrap(err, "failed to make / private")
+	}
+	dir, err := ioutil.TempDir("", ".cer.mqueue.*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(dir)
+	if err = unix.Mount("mqueue", dir, "mqueue", 0, ""); err != nil {
+		return errors.Wrap(err, "failed to mount mqueue")
+	}
+	defer unix.Unmount(dir, unix.MNT_DETACH)
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, info := range infos {
+		if err = os.Remove(path.Join(dir, info.Name())); err != nil {
+			return errors.Wrapf(err, "failed to remove POSIX message queue %s", info.Name())
+		}
+	}
+	if infos, err = ioutil.ReadDir(dir); err != nil {
+		return err
+	}
+	if len(infos) != 0 {
+		return errors.Errorf("%d POSIX message queues still exist after removal", len(infos))
+	}
+	return nil
+}
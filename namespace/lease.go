@@ -0,0 +1,190 @@
+package namespace
+
+import (
+	"sync"
+	"time"
+
+	apiservices "github.com/YLonely/cer-manager/api/services"
+	"github.com/YLonely/cer-manager/api/types"
+	"github.com/YLonely/cer-manager/log"
+	"github.com/YLonely/cer-manager/utils"
+)
+
+// reapInterval is the interval the reaper looks for the expired leases
+var reapInterval = time.Second
+
+// NewLeaseKeeper returns a LeaseKeeper of the namespaces of type t and starts its reaper,
+// expire is called by the reaper with the handle and the id of each expired lease
+func NewLeaseKeeper(t types.NamespaceType, expire func(handle string, id string)) *LeaseKeeper {
+	k := &LeaseKeeper{
+		t:         t,
+		leases:    map[string]types.Lease{},
+		ids:       map[string]string{},
+		reclaimed: map[string]bool{},
+		expire:    expire,
+		stopC:     make(chan struct{}),
+	}
+	go k.reap(reapInterval)
+	return k
+}
+
+// LeaseKeeper keeps the leases on the namespaces checked out from a manager.
+// The manager should hold its own lock while calling Grant, Revoke and Take.
+type LeaseKeeper struct {
+	m sync.Mutex
+	t types.NamespaceType
+	// leases maps the namespace handle to its lease
+	leases map[string]types.Lease
+	// ids maps the lease id to the namespace handle
+	ids map[string]string
+	// reclaimed are the ids of the expired leases whose namespaces are taken by expire
+	reclaimed map[string]bool
+	expire    func(handle string, id string)
+	notify    func(types.Lease)
+	stopC     chan struct{}
+	stopOnce  sync.Once
+}
+
+// Notify registers f to be called with each expired lease once its namespace is put back
+func (k *LeaseKeeper) Notify(f func(types.Lease)) {
+	k.m.Lock()
+	defer k.m.Unlock()
+	k.notify = f
+}
+
+// Grant grants a lease of ttl on the namespace of handle, the previous lease on it is replaced
+func (k *LeaseKeeper) Grant(handle string, ref types.Reference, ttl time.Duration) (types.Lease, error) {
+	if ttl <= 0 {
+		return types.Lease{}, apiservices.Errorf(apiservices.CodeInvalidArgument, "non-positive lease ttl %v is invalid", ttl)
+	}
+	id, err := utils.RandomID()
+	if err != nil {
+		return types.Lease{}, err
+	}
+	k.m.Lock()
+	defer k.m.Unlock()
+	if old, exists := k.leases[handle]; exists {
+		delete(k.ids, old.ID)
+	}
+	l := types.Lease{
+		ID:            id,
+		NamespaceType: k.t,
+		NamespaceID:   handle,
+		Ref:           ref,
+		TTL:           ttl,
+		Expire:        time.Now().Add(ttl),
+	}
+	k.leases[handle] = l
+	k.ids[id] = handle
+	return l, nil
+}
+
+// Renew extends the lease of id by ttl from now, the ttl of the lease is used if ttl is zero
+func (k *LeaseKeeper) Renew(id string, ttl time.Duration) (types.Lease, error) {
+	if ttl < 0 {
+		return types.Lease{}, apiservices.Errorf(apiservices.CodeInvalidArgument, "negative lease ttl %v is invalid", ttl)
+	}
+	k.m.Lock()
+	defer k.m.Unlock()
+	handle, exists := k.ids[id]
+	if !exists {
+		return types.Lease{}, apiservices.Errorf(apiservices.CodeNotFound, "%s lease %s does not exist or has expired", k.t, id)
+	}
+	l := k.leases[handle]
+	now := time.Now()
+	if l.Expired(now) {
+		return types.Lease{}, apiservices.Errorf(apiservices.CodeNotFound, "%s lease %s has expired", k.t, id)
+	}
+	if ttl != 0 {
+		l.TTL = ttl
+	}
+	l.Expire = now.Add(l.TTL)
+	k.leases[handle] = l
+	return l, nil
+}
+
+// Lookup returns the lease of id
+func (k *LeaseKeeper) Lookup(id string) (types.Lease, error) {
+	k.m.Lock()
+	defer k.m.Unlock()
+	handle, exists := k.ids[id]
+	if !exists {
+		return types.Lease{}, apiservices.Errorf(apiservices.CodeNotFound, "%s lease %s does not exist or has expired", k.t, id)
+	}
+	return k.leases[handle], nil
+}
+
+// Revoke removes the lease on the namespace of handle if there is one
+func (k *LeaseKeeper) Revoke(handle string) {
+	k.m.Lock()
+	defer k.m.Unlock()
+	if l, exists := k.leases[handle]; exists {
+		delete(k.ids, l.ID)
+		delete(k.leases, handle)
+	}
+}
+
+// Take removes the lease of id on handle if it is still there and has expired,
+// it reports whether the namespace should be reclaimed
+func (k *LeaseKeeper) Take(handle string, id string) bool {
+	k.m.Lock()
+	defer k.m.Unlock()
+	l, exists := k.leases[handle]
+	if !exists || l.ID != id || !l.Expired(time.Now()) {
+		return false
+	}
+	delete(k.ids, l.ID)
+	delete(k.leases, handle)
+	k.reclaimed[id] = true
+	return true
+}
+
+// Leases returns all the leases
+func (k *LeaseKeeper) Leases() []types.Lease {
+	k.m.Lock()
+	defer k.m.Unlock()
+	ret := make([]types.Lease, 0, len(k.leases))
+	for _, l := range k.leases {
+		ret = append(ret, l)
+	}
+	return ret
+}
+
+// Stop stops the reaper
+func (k *LeaseKeeper) Stop() {
+	k.stopOnce.Do(func() {
+		close(k.stopC)
+	})
+}
+
+func (k *LeaseKeeper) reap(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-k.stopC:
+			return
+		case now := <-ticker.C:
+			var expired []types.Lease
+			k.m.Lock()
+			for _, l := range k.leases {
+				if l.Expired(now) {
+					expired = append(expired, l)
+				}
+			}
+			k.m.Unlock()
+			// expire takes the lock of the manager, which must be taken before the lock of the keeper
+			for _, l := range expired {
+				log.Raw().Infof("%s lease %s on namespace %s of %s expired", k.t, l.ID, l.NamespaceID, l.Ref)
+				k.expire(l.NamespaceID, l.ID)
+				k.m.Lock()
+				reclaimed, notify := k.reclaimed[l.ID], k.notify
+				delete(k.reclaimed, l.ID)
+				k.m.Unlock()
+				if reclaimed && notify != nil {
+					notify(l)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package namespace
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	apiservices "github.com/YLonely/cer-manager/api/services"
+	"github.com/YLonely/cer-manager/api/types"
+	"github.com/pkg/errors"
+)
+
+// leaseManager puts back its namespaces the way the managers do, under its own lock
+type leaseManager struct {
+	*LeaseKeeper
+	m    sync.Mutex
+	used map[string]bool
+	// puts counts the namespaces put back by Put or by the reaper
+	puts int
+}
+
+func newLeaseManager() *leaseManager {
+	m := &leaseManager{used: map[string]bool{}}
+	m.LeaseKeeper = NewLeaseKeeper(types.NamespaceUTS, m.expire)
+	return m
+}
+
+func (m *leaseManager) get(handle string, ttl time.Duration) (types.Lease, error) {
+	m.m.Lock()
+	defer m.m.Unlock()
+	m.used[handle] = true
+	return m.Grant(handle, types.Reference{Name: "test"}, ttl)
+}
+
+func (m *leaseManager) Put(handle string) error {
+	m.m.Lock()
+	defer m.m.Unlock()
+	if !m.used[handle] {
+		return apiservices.Errorf(apiservices.CodeNotFound, "%s is not in use", handle)
+	}
+	delete(m.used, handle)
+	m.Revoke(handle)
+	m.puts++
+	return nil
+}
+
+func (m *leaseManager) expire(handle string, id string) {
+	m.m.Lock()
+	defer m.m.Unlock()
+	if !m.Take(handle, id) {
+		return
+	}
+	delete(m.used, handle)
+	m.puts++
+}
+
+func TestLeaseTake(t *testing.T) {
+	tests := []struct {
+		name string
+		// prepare runs on the expired lease l before Take
+		prepare func(m *leaseManager, l types.Lease) error
+		want    bool
+	}{
+		{
+			name:    "expired",
+			prepare: func(m *leaseManager, l types.Lease) error { return nil },
+			want:    true,
+		},
+		{
+			name:    "put back",
+			prepare: func(m *leaseManager, l types.Lease) error { return m.Put(l.NamespaceID) },
+			want:    false,
+		},
+		{
+			name: "granted again",
+			prepare: func(m *leaseManager, l types.Lease) error {
+				_, err := m.get(l.NamespaceID, time.Nanosecond)
+				return err
+			},
+			want: false,
+		},
+		{
+			name: "revoked",
+			prepare: func(m *leaseManager, l types.Lease) error {
+				m.Revoke(l.NamespaceID)
+				return nil
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newLeaseManager()
+			defer m.Stop()
+			l, err := m.get("handle", time.Nanosecond)
+			if err != nil {
+				t.Fatal(err)
+			}
+			time.Sleep(time.Millisecond)
+			if err = tt.prepare(m, l); err != nil {
+				t.Fatal(err)
+			}
+			if got := m.Take(l.NamespaceID, l.ID); got != tt.want {
+				t.Fatalf("Take() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLeaseRenew(t *testing.T) {
+	m := newLeaseManager()
+	defer m.Stop()
+	l, err := m.get("handle", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	renewed, err := m.Renew(l.ID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renewed.TTL != time.Hour || renewed.Expire.Before(l.Expire) {
+		t.Fatalf("Renew() = %+v, want the ttl kept and the expiry extended from %v", renewed, l.Expire)
+	}
+	if _, err = m.Renew(l.ID, -time.Second); !errors.Is(err, apiservices.ErrInvalidArgument) {
+		t.Fatalf("Renew() with a negative ttl returned %v", err)
+	}
+	if err = m.Put(l.NamespaceID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = m.Renew(l.ID, 0); !errors.Is(err, apiservices.ErrNotFound) {
+		t.Fatalf("Renew() after Put returned %v", err)
+	}
+	if _, err = m.Lookup(l.ID); !errors.Is(err, apiservices.ErrNotFound) {
+		t.Fatalf("Lookup() after Put returned %v", err)
+	}
+}
+
+// TestLeaseExpireRacePut races the expiry of leases against Put, each namespace must be put back exactly once
+func TestLeaseExpireRacePut(t *testing.T) {
+	const n = 200
+	m := newLeaseManager()
+	defer m.Stop()
+	leases := make([]types.Lease, n)
+	for i := range leases {
+		l, err := m.get(fmt.Sprintf("handle-%d", i), time.Nanosecond)
+		if err != nil {
+			t.Fatal(err)
+		}
+		leases[i] = l
+	}
+	time.Sleep(time.Millisecond)
+	var (
+		wg     sync.WaitGroup
+		failed = make(chan error, n)
+	)
+	for _, l := range leases {
+		wg.Add(2)
+		go func(l types.Lease) {
+			defer wg.Done()
+			if err := m.Put(l.NamespaceID); err != nil && !errors.Is(err, apiservices.ErrNotFound) {
+				failed <- err
+			}
+		}(l)
+		go func(l types.Lease) {
+			defer wg.Done()
+			m.expire(l.NamespaceID, l.ID)
+		}(l)
+	}
+	wg.Wait()
+	close(failed)
+	for err := range failed {
+		t.Error(err)
+	}
+	if m.puts != n || len(m.used) != 0 {
+		t.Fatalf("%d namespaces are put back and %d are in use, want %d and 0", m.puts, len(m.used), n)
+	}
+	if leases := m.Leases(); len(leases) != 0 {
+		t.Fatalf("%d leases are left", len(leases))
+	}
+}
+
+func TestLeaseReaperNotify(t *testing.T) {
+	defer func(interval time.Duration) { reapInterval = interval }(reapInterval)
+	reapInterval = time.Millisecond
+	m := newLeaseManager()
+	defer m.Stop()
+	notified := make(chan types.Lease, 2)
+	m.Notify(func(l types.Lease) { notified <- l })
+	expired, err := m.get("expired", 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	put, err := m.get("put", 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = m.Put(put.NamespaceID); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case l := <-notified:
+		if l.ID != expired.ID {
+			t.Fatalf("notified of lease %s, want %s", l.ID, expired.ID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("not notified of the expired lease")
+	}
+	select {
+	case l := <-notified:
+		t.Fatalf("notified of lease %s whose namespace is put back", l.ID)
+	case <-time.After(50 * time.Millisecond):
+	}
+	m.m.Lock()
+	defer m.m.Unlock()
+	if m.puts != 2 {
+		t.Fatalf("%d namespaces are put back, want 2", m.puts)
+	}
+}
@@ -1,11 +1,50 @@
 package namespace
 
-import "github.com/YLonely/cer-manager/api/types"
+import (
+	"os"
+	"strings"
+	"time"
+
+	apiservices "github.com/YLonely/cer-manager/api/services"
+	"github.com/YLonely/cer-manager/api/types"
+)
 
 // Manager manages different types of namespace
 type Manager interface {
-	Get(ref types.Reference, extraRefs ...types.Reference) (fd int, info interface{}, err error)
-	Put(fd int) error
+	// Get returns a namespace file of ref along with its handle, the file is still owned by the manager.
+	// The handle is unique and never reused, it is used to put the namespace back.
+	// If no namespace is available, Get waits up to wait for one in the order of arrival before it fails
+	Get(ref types.Reference, wait time.Duration, extraRefs ...types.Reference) (handle string, f *os.File, info interface{}, err error)
+	Put(handle string) error
+	// Refs returns the references the namespace checked out with handle is got for
+	Refs(handle string) ([]types.Reference, error)
+	// Lease grants a lease of ttl on the namespace checked out with handle, the namespace is put back once the lease expires
+	Lease(handle string, ttl time.Duration) (types.Lease, error)
+	// Renew extends the lease of id by ttl, the ttl of the lease is used if ttl is zero
+	Renew(id string, ttl time.Duration) (types.Lease, error)
+	// Lookup returns the lease of id
+	Lookup(id string) (types.Lease, error)
+	// Leases returns the leases on the namespaces checked out
+	Leases() []types.Lease
+	// Notify registers f to be called with each expired lease once its namespace is put back
+	Notify(f func(types.Lease))
 	Update(ref types.Reference, capacity int) error
+	// Autoscale scales the namespace set of ref between min and max following the demand, Update of ref is rejected from then on
+	Autoscale(ref types.Reference, min, max int) error
+	// Health returns the results of the health checks of the idle namespaces of each reference
+	Health() []types.HealthStatus
 	CleanUp() error
 }
+
+// ErrAutoscaled returns the error of an Update of the namespace set of type t for ref which is scaled by the autoscaler
+func ErrAutoscaled(t types.NamespaceType, ref types.Reference) error {
+	return apiservices.Errorf(apiservices.CodeInvalidArgument, "%s namespace set of %s is autoscaled", strings.ToUpper(string(t)), ref)
+}
+
+// ErrUsedUp returns the error of a Get that finds no namespace of type t for ref after waiting for wait
+func ErrUsedUp(t types.NamespaceType, ref types.Reference, wait time.Duration) error {
+	if wait <= 0 {
+		return apiservices.Errorf(apiservices.CodeExhausted, "%s namespace of %s is used up", strings.ToUpper(string(t)), ref)
+	}
+	return apiservices.Errorf(apiservices.CodeExhausted, "%s namespace of %s is still used up after waiting %s", strings.ToUpper(string(t)), ref, wait)
+}
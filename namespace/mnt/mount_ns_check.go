@@ -0,0 +1,48 @@
+package mnt
+
+import (
+	"os"
+	"path"
+
+	"github.com/pkg/errors"
+)
+
+// checkBundle runs in an idle mount namespace, it checks that the bundle still exists
+// and the rootfs is the overlay of the bundle with the mounts populated on top of it
+func checkBundle(args map[string]interface{}) ([]byte, error) {
+	bundle, ok := args["bundle"].(string)
+	if !ok || bundle == "" {
+		return nil, errors.New("bundle must be provided")
+	}
+	upper, rootfs := path.Join(bundle, "upper"), path.Join(bundle, "rootfs")
+	if _, err := os.Stat(upper); err != nil {
+		return nil, errors.Wrap(err, "upper dir of the bundle is gone")
+	}
+	infos, err := readMountinfo("/proc/self/mountinfo")
+	if err != nil {
+		return nil, err
+	}
+	root := lastMountAt(infos, rootfs)
+	if root == nil {
+		return nil, errors.Errorf("rootfs %s is not mounted", rootfs)
+	}
+	if root.fstype != "overlay" {
+		return nil, errors.Errorf("rootfs %s is a %s mount instead of overlay", rootfs, root.fstype)
+	}
+	hasUpper := false
+	for _, opt := range root.superOptions {
+		if opt == "upperdir="+upper {
+			hasUpper = true
+		}
+	}
+	if !hasUpper {
+		return nil, errors.Errorf("upper dir of rootfs %s is not %s", rootfs, upper)
+	}
+	for _, m := range mounts {
+		target := path.Join(rootfs, m.target)
+		if info := lastMountAt(infos, target); info == nil || info.fstype != m.Type {
+			return nil, errors.Errorf("%s is not mounted at %s", m.Type, target)
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,67 @@
+package mnt
+
+import (
+	"path"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// cloneBundle runs in a copy of the template mount namespace, it mounts a new overlay of src in bundle,
+// moves the mounts of the template rootfs onto it and gives it its own pseudo filesystems and files
+func cloneBundle(args map[string]interface{}) ([]byte, error) {
+	src, ok := args["src"].(string)
+	if !ok || src == "" {
+		return nil, errors.New("src must be provided")
+	}
+	bundle, ok := args["bundle"].(string)
+	if !ok || bundle == "" {
+		return nil, errors.New("bundle must be provided")
+	}
+	checkpoint, ok := args["checkpoint"].(string)
+	if !ok || checkpoint == "" {
+		return nil, errors.New("checkpoint must be provided")
+	}
+	template, ok := args["template"].(string)
+	if !ok || template == "" {
+		return nil, errors.New("template must be provided")
+	}
+	if err := unix.Mount("none", "/", "", unix.MS_REC|unix.MS_PRIVATE, ""); err != nil {
+		return nil, err
+	}
+	rootfs, templateRootfs := path.Join(bundle, "rootfs"), path.Join(template, "rootfs")
+	if err := mountOverlay(src, path.Join(bundle, "upper"), path.Join(bundle, "work"), rootfs); err != nil {
+		return nil, err
+	}
+	if err := moveSubmounts(templateRootfs, rootfs, checkpoint); err != nil {
+		return nil, err
+	}
+	if err := unix.Unmount(templateRootfs, unix.MNT_DETACH); err != nil {
+		return nil, errors.Wrap(err, "failed to unmount the template rootfs")
+	}
+	if err := renewMounts(rootfs); err != nil {
+		return nil, err
+	}
+	if err := restoreFiles(rootfs, checkpoint); err != nil {
+		return nil, errors.Wrap(err, "failed to restore files")
+	}
+	return nil, nil
+}
+
+// renewMounts replaces the tmpfs and devpts copied from the template, which share their contents with it,
+// with new instances
+func renewMounts(rootfs string) error {
+	for _, m := range mounts {
+		if m.Type != "tmpfs" && m.Type != "devpts" {
+			continue
+		}
+		target := path.Join(rootfs, m.target)
+		if err := unix.Unmount(target, unix.MNT_DETACH); err != nil {
+			return errors.Wrapf(err, "failed to unmount %s", target)
+		}
+		if err := m.Mount.Mount(target); err != nil {
+			return errors.Wrapf(err, "mount(src:%s,dest:%s,type:%s) failed", m.Source, target, m.Type)
+		}
+	}
+	return nil
+}
@@ -12,9 +12,12 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"syscall"
+	"time"
 
 	"github.com/pkg/errors"
 
+	apiservices "github.com/YLonely/cer-manager/api/services"
 	"github.com/YLonely/cer-manager/api/types"
 	"github.com/YLonely/cer-manager/log"
 	"github.com/YLonely/cer-manager/mount"
@@ -29,24 +32,40 @@ import (
 func init() {
 	namespace.PutNamespaceFunction(namespace.NamespaceFunctionKeyCreate, types.NamespaceMNT, populateBundle)
 	namespace.PutNamespaceFunction(namespace.NamespaceFunctionKeyRelease, types.NamespaceMNT, depopulateBundle)
+	namespace.PutNamespaceFunction(namespace.NamespaceFunctionKeyReset, types.NamespaceMNT, resetBundle)
+	namespace.PutNamespaceFunction(namespace.NamespaceFunctionKeyClone, types.NamespaceMNT, cloneBundle)
+	namespace.PutNamespaceFunction(namespace.NamespaceFunctionKeyCheck, types.NamespaceMNT, checkBundle)
 }
 
-func NewManager(root string, capacities []int, refs []types.Reference, provider rootfs.Provider, supplier types.Supplier) (namespace.Manager, error) {
+// NewManager returns the manager of the mount namespaces of refs, modes decides what becomes of the namespaces
+// of each reference put back. The namespaces of the references in owners are created in their owner user namespaces
+func NewManager(root string, capacities []int, refs []types.Reference, modes []PutMode, provider rootfs.Provider, supplier types.Supplier, refiller *namespace.Refiller, budget *namespace.Budget, owners *namespace.Owners) (namespace.Manager, error) {
 	var err error
 	rootfsParentDir := path.Join(root, "rootfs")
 	if err = os.MkdirAll(rootfsParentDir, 0755); err != nil {
 		return nil, errors.Wrap(err, "failed to create rootfs dir")
 	}
+	handles, err := namespace.NewHandleIssuer(types.NamespaceMNT)
+	if err != nil {
+		return nil, err
+	}
 	m := &mountManager{
 		root:        root,
-		sets:        map[string]*namespace.Set{},
+		sets:        map[string]*mountSet{},
 		allBundles:  map[int]string{},
-		usedBundles: map[int]bundleInfo{},
+		usedBundles: map[string]bundleInfo{},
+		handles:     handles,
 		provider:    provider,
 		supplier:    supplier,
+		refiller:    refiller,
+		budget:      budget,
+		owners:      owners,
 	}
+	m.LeaseKeeper = namespace.NewLeaseKeeper(types.NamespaceMNT, m.expire)
+	m.autoscaler = namespace.NewAutoscaler(types.NamespaceMNT, m.resize)
+	m.health = namespace.NewHealthChecker(types.NamespaceMNT, namespace.HealthCheckInterval)
 	for i, ref := range refs {
-		m.initSet(ref, capacities[i])
+		m.initSet(ref, capacities[i], modes[i])
 	}
 	return m, nil
 }
@@ -54,24 +73,57 @@ func NewManager(root string, capacities []int, refs []types.Reference, provider
 var _ namespace.Manager = &mountManager{}
 
 type mountManager struct {
-	sets map[string]*namespace.Set
-	// allBundles maps namespace fd to it's bundle path
+	*namespace.LeaseKeeper
+	sets map[string]*mountSet
+	// allBundles maps namespace fd to it's bundle path, it is guarded by bundlesMu
+	// as the namespaces are created by the refiller without holding m
 	allBundles map[int]string
+	bundlesMu  sync.Mutex
 	provider   rootfs.Provider
 	root       string
-	// usedBundles maps fd to it's basic info
-	usedBundles map[int]bundleInfo
+	// usedBundles maps handle to it's basic info
+	usedBundles map[string]bundleInfo
+	handles     *namespace.HandleIssuer
+	autoscaler  *namespace.Autoscaler
+	health      *namespace.HealthChecker
+	refiller    *namespace.Refiller
+	budget      *namespace.Budget
+	owners      *namespace.Owners
 	m           sync.Mutex
 	supplier    types.Supplier
 }
 
+type mountSet struct {
+	*namespace.Set
+	mode PutMode
+	// src is the rootfs the overlay of each namespace is on top of
+	src        string
+	checkpoint string
+	// template is the namespace populated from scratch, the namespaces in the set are cloned from it
+	template *os.File
+	// base are the read-only layers of the rootfs, the sets of the same base image have the same base.
+	// It is empty if the layers are unknown
+	base string
+	// meter charges the upper dirs of the namespaces to the budget
+	meter *namespace.Meter
+	owner bundleOwner
+}
+
+// bundleOwner is the user namespace the namespaces of a set are created in, the helpers populating
+// and resetting the bundles run as its root. The path is empty if it is the user namespace of the daemon
+type bundleOwner struct {
+	path string
+	// uid and gid are the ids of the host the root of the user namespace is mapped to, they own the bundles
+	uid, gid int
+}
+
 type bundleInfo struct {
 	bundle string
 	ref    types.Reference
 	f      *os.File
 }
 
-func (m *mountManager) initSet(ref types.Reference, capacity int) error {
+func (m *mountManager) initSet(ref types.Reference, capacity int, mode PutMode) error {
 	mounts, err := m.provider.Prepare(ref, ref.Digest()+"-key")
 	if err != nil {
 		return errors.Wrap(err, "error prepare rootfs for "+ref.String())
@@ -83,7 +135,10 @@ func (m *mountManager) initSet(ref types.Reference, capacity int) error {
 	if err = os.MkdirAll(rootfsDir, 0755); err != nil {
 		return errors.Wrap(err, "error create dir for "+ref.String())
 	}
+	// the upper dir of the snapshot is made for ref alone, so only the lower dirs tell the base image
+	base := ""
 	if isOverlayMounts(mounts) {
+		base = strings.Join(mounts[len(mounts)-1].Lowers(), ":")
 		makeOverlaysReadOnly(mounts)
 	}
 	// umount it first, avoid stacked mount
@@ -95,87 +150,261 @@ func (m *mountManager) initSet(ref types.Reference, capacity int) error {
 	if err != nil {
 		return errors.Wrapf(err, "failed to get checkpoint for %s", ref)
 	}
-	set, err := namespace.NewSet(capacity, m.makeNewNamespaceCreator(rootfsDir, checkpoint), m.makePreRelease())
+	// the upper dir of a new bundle is unknown until it is populated, so the one measured last is charged in advance
+	meter := m.budget.Meter(types.Resources{Namespaces: 1}, func(f *os.File) types.Resources {
+		return types.Resources{Namespaces: 1, UpperBytes: dirUsage(filepath.Join(m.bundleOf(f), "upper"))}
+	})
+	release := meter.Releaser(m.makePreRelease())
+	owner := bundleOwner{path: m.owners.Path(ref, types.NamespaceMNT)}
+	owner.uid, owner.gid = m.owners.Root(ref, types.NamespaceMNT)
+	template, err := meter.Creator(m.makeNewNamespaceCreator(rootfsDir, checkpoint, owner))()
+	if err != nil {
+		return errors.Wrapf(err, "failed to create the template namespace for %s", ref)
+	}
+	set, err := namespace.NewSet(capacity, meter.Creator(m.makeCloneCreator(rootfsDir, checkpoint, template, owner)), release)
 	if err != nil {
+		if err := release(template); err != nil {
+			log.Raw().WithError(err).Errorf("failed to release the template namespace of %s", ref)
+		}
 		return errors.Wrapf(err, "failed to create namespace set for %s", ref)
 	}
-	m.sets[ref.Digest()] = set
+	set.SetAdmission(meter.Admit)
+	m.refiller.Watch(types.NamespaceMNT, ref, set)
+	m.health.Watch(ref, set, func(f *os.File) error { return m.check(f, owner) })
+	m.sets[ref.Digest()] = &mountSet{
+		Set:        set,
+		mode:       mode,
+		src:        rootfsDir,
+		checkpoint: checkpoint,
+		template:   template,
+		base:       base,
+		meter:      meter,
+		owner:      owner,
+	}
 	return nil
 }
 
-func (mgr *mountManager) Get(ref types.Reference, extraRefs ...types.Reference) (fd int, info interface{}, err error) {
-	if len(extraRefs) > 0 {
-		err = errors.New("multiple references is not supported")
+// bundleOf returns the bundle path of the namespace f, or an empty string if f is not created by us
+func (m *mountManager) bundleOf(f *os.File) string {
+	m.bundlesMu.Lock()
+	defer m.bundlesMu.Unlock()
+	return m.allBundles[int(f.Fd())]
+}
+
+// Get returns a namespace of ref, the info is its bundle. If extraRefs are given, a namespace of the one
+// of them sharing the base image of ref may be returned instead when ref has none idle, the info is a types.Selection then
+func (mgr *mountManager) Get(ref types.Reference, wait time.Duration, extraRefs ...types.Reference) (handle string, f *os.File, info interface{}, err error) {
+	mgr.m.Lock()
+	set, exists := mgr.sets[ref.Digest()]
+	target, reason := ref, ""
+	if exists && len(extraRefs) > 0 {
+		target, f, reason = mgr.pick(ref, set, extraRefs)
+	}
+	mgr.m.Unlock()
+	if !exists {
+		err = apiservices.Errorf(apiservices.CodeNotFound, "MNT namespace of %s is not managed by us", ref)
+		return
+	}
+	if f == nil {
+		// wait without holding the lock, the namespace is handed over by the refiller
+		f = set.Wait(wait)
+	}
+	if f == nil {
+		mgr.autoscaler.Miss(ref)
+		err = namespace.ErrUsedUp(types.NamespaceMNT, ref, wait)
 		return
 	}
 	mgr.m.Lock()
 	defer mgr.m.Unlock()
-	if set, exists := mgr.sets[ref.Digest()]; exists {
-		f := set.Get()
-		if f == nil {
-			err = errors.Errorf("MNT namespace of %s is used up", ref)
-			return
-		}
-		info = mgr.allBundles[int(f.Fd())]
-		fd = int(f.Fd())
-		mgr.usedBundles[fd] = bundleInfo{
-			ref:    ref,
-			bundle: info.(string),
-			f:      f,
-		}
-		go func() {
-			mgr.m.Lock()
-			defer mgr.m.Unlock()
-			if err := set.CreateOne(); err != nil {
-				log.Raw().WithError(err).Errorf("failed to create a new MNT namespace for %s", ref)
-			}
-		}()
+	bundle := mgr.bundleOf(f)
+	if handle, err = mgr.handles.Next(); err != nil {
+		mgr.sets[target.Digest()].Add(f)
+		f = nil
 		return
 	}
-	err = errors.Errorf("MNT namespace of %s is not managed by us", ref)
+	mgr.autoscaler.Checkout(handle, target)
+	mgr.usedBundles[handle] = bundleInfo{
+		ref:    target,
+		bundle: bundle,
+		f:      f,
+	}
+	info = bundle
+	if len(extraRefs) > 0 {
+		info = types.Selection{Ref: target, Reason: reason, Info: bundle}
+	}
 	return
 }
 
-func (mgr *mountManager) Put(fd int) error {
+// pick takes an idle namespace of ref, or of the first of candidates with the same base image if ref has none.
+// It returns the reference picked and why, f is nil if none of them has an idle namespace and ref is picked to wait on
+func (mgr *mountManager) pick(ref types.Reference, set *mountSet, candidates []types.Reference) (target types.Reference, f *os.File, reason string) {
+	if f = set.Get(); f != nil {
+		return ref, f, "the reference has an idle namespace"
+	}
+	if set.base != "" {
+		for _, c := range candidates {
+			other, exists := mgr.sets[c.Digest()]
+			if !exists || other == set || other.base != set.base {
+				continue
+			}
+			if f = other.Get(); f != nil {
+				return c, f, fmt.Sprintf("%s has no idle namespace, %s is of the same base image", ref, c)
+			}
+		}
+	}
+	return ref, nil, "no reference of the same base image has an idle namespace"
+}
+
+func (mgr *mountManager) Put(handle string) error {
 	mgr.m.Lock()
 	defer mgr.m.Unlock()
-	info, exists := mgr.usedBundles[fd]
+	return mgr.put(handle)
+}
+
+func (mgr *mountManager) put(handle string) error {
+	info, exists := mgr.usedBundles[handle]
 	if !exists {
-		return errors.Errorf("invalid fd %d", fd)
+		return mgr.handles.Unknown(handle)
 	}
+	delete(mgr.usedBundles, handle)
+	mgr.Revoke(handle)
+	mgr.autoscaler.Return(handle)
+	set := mgr.sets[info.ref.Digest()]
+	if set.mode == PutModeRecycle {
+		go func() {
+			if err := mgr.reset(set, info); err != nil {
+				log.Raw().WithError(err).Errorf("failed to reset bundle %s of %s, discard it", info.bundle, info.ref)
+				set.Release(info.f)
+				return
+			}
+			// the upper dir may not be as small as the pristine one after the reset
+			set.meter.Remeasure(info.f)
+			set.Recycle(info.f)
+		}()
+		return nil
+	}
+	// tearing down the bundle takes a while, the handle is already gone so it is not put back twice
 	go func() {
-		mgr.m.Lock()
-		defer mgr.m.Unlock()
-		if _, exists := mgr.usedBundles[fd]; !exists {
-			return
-		}
-		defer info.f.Close()
-		defer delete(mgr.usedBundles, fd)
-		// maybe not necessary
-		if err := mgr.makePreRelease()(info.f); err != nil {
-			log.Raw().WithError(err).Errorf("failed to release the MNT namespace of fd %d", info.f.Fd())
+		if err := set.Release(info.f); err != nil {
+			log.Raw().WithError(err).Errorf("failed to release bundle %s of %s", info.bundle, info.ref)
 		}
 	}()
 	return nil
 }
 
+func (mgr *mountManager) Lease(handle string, ttl time.Duration) (types.Lease, error) {
+	mgr.m.Lock()
+	defer mgr.m.Unlock()
+	info, exists := mgr.usedBundles[handle]
+	if !exists {
+		return types.Lease{}, mgr.handles.Unknown(handle)
+	}
+	return mgr.Grant(handle, info.ref, ttl)
+}
+
+func (mgr *mountManager) Refs(handle string) ([]types.Reference, error) {
+	mgr.m.Lock()
+	defer mgr.m.Unlock()
+	info, exists := mgr.usedBundles[handle]
+	if !exists {
+		return nil, mgr.handles.Unknown(handle)
+	}
+	return []types.Reference{info.ref}, nil
+}
+
+// expire puts back the namespace whose lease expired
+func (mgr *mountManager) expire(handle string, id string) {
+	mgr.m.Lock()
+	defer mgr.m.Unlock()
+	if !mgr.Take(handle, id) {
+		return
+	}
+	if err := mgr.put(handle); err != nil {
+		log.Raw().WithError(err).Errorf("failed to put back MNT namespace %s with expired lease", handle)
+	}
+}
+
 func (mgr *mountManager) Update(ref types.Reference, capacity int) error {
 	mgr.m.Lock()
 	defer mgr.m.Unlock()
 	set, exists := mgr.sets[ref.Digest()]
 	if !exists {
-		if err := mgr.initSet(ref, capacity); err != nil {
+		if err := mgr.initSet(ref, capacity, PutModeDestroy); err != nil {
 			return err
 		}
 		return nil
 	}
+	if mgr.autoscaler.Enabled(ref) {
+		return namespace.ErrAutoscaled(types.NamespaceMNT, ref)
+	}
 	return set.Update(capacity)
 }
 
+// resize is called by the autoscaler, the namespaces are created by the refiller instead of under the lock
+func (mgr *mountManager) resize(ref types.Reference, capacity int) error {
+	mgr.m.Lock()
+	set, exists := mgr.sets[ref.Digest()]
+	mgr.m.Unlock()
+	if !exists {
+		return apiservices.Errorf(apiservices.CodeNotFound, "MNT namespace of %s is not managed by us", ref)
+	}
+	return set.Resize(capacity)
+}
+
+func (mgr *mountManager) Autoscale(ref types.Reference, min, max int) error {
+	mgr.m.Lock()
+	defer mgr.m.Unlock()
+	set, exists := mgr.sets[ref.Digest()]
+	if !exists {
+		return apiservices.Errorf(apiservices.CodeNotFound, "MNT namespace of %s is not managed by us", ref)
+	}
+	return mgr.autoscaler.Enable(ref, set.Target(), min, max)
+}
+
+// reset enters the namespace of info and resets its bundle to the state right after it is populated
+func (mgr *mountManager) reset(set *mountSet, info bundleInfo) error {
+	helper, err := namespace.NewNamespaceExecEnterHelper(
+		namespace.NamespaceFunctionKeyReset,
+		types.NamespaceMNT,
+		fmt.Sprintf("/proc/%d/fd/%d", os.Getpid(), int(info.f.Fd())),
+		map[string]string{
+			"src":        set.src,
+			"bundle":     info.bundle,
+			"checkpoint": set.checkpoint,
+		},
+	)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create namespace helper for %d with bundle %s", info.f.Fd(), info.bundle)
+	}
+	helper.JoinUserNamespace(set.owner.path)
+	return helper.Do(true)
+}
+
+// check enters the namespace f created in owner and checks its bundle
+func (mgr *mountManager) check(f *os.File, owner bundleOwner) error {
+	bundle := mgr.bundleOf(f)
+	if bundle == "" {
+		return errors.Errorf("bundle path of fd %d does not exist", f.Fd())
+	}
+	helper, err := namespace.NewNamespaceExecEnterHelper(
+		namespace.NamespaceFunctionKeyCheck,
+		types.NamespaceMNT,
+		fmt.Sprintf("/proc/%d/fd/%d", os.Getpid(), int(f.Fd())),
+		map[string]string{
+			"bundle": bundle,
+		},
+	)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create namespace helper for %d with bundle %s", f.Fd(), bundle)
+	}
+	helper.JoinUserNamespace(owner.path)
+	return helper.Do(true)
+}
+
 func (m *mountManager) makePreRelease() func(*os.File) error {
 	return func(f *os.File) error {
-		bundle, exists := m.allBundles[int(f.Fd())]
-		if !exists {
+		bundle := m.bundleOf(f)
+		if bundle == "" {
 			return errors.Errorf("bundle path of fd %d does not exist", f.Fd())
 		}
 		helper, err := namespace.NewNamespaceExecEnterHelper(
@@ -196,11 +425,18 @@ func (m *mountManager) makePreRelease() func(*os.File) error {
 	}
 }
 
+func (mgr *mountManager) Health() []types.HealthStatus {
+	return mgr.health.Status()
+}
+
 func (mgr *mountManager) CleanUp() error {
+	mgr.Stop()
+	mgr.autoscaler.Stop()
+	mgr.health.Stop()
 	var last error
 	for _, info := range mgr.usedBundles {
 		log.Raw().Warnf("bundle %s of %s is being used", info.bundle, info.ref)
-		if err := mgr.makePreRelease()(info.f); err != nil {
+		if err := mgr.sets[info.ref.Digest()].Release(info.f); err != nil {
 			last = err
 			log.Raw().WithError(err).Errorf("failed to release bundle %s of %s", info.bundle, info.ref)
 		}
@@ -221,6 +457,10 @@ func (mgr *mountManager) CleanUp() error {
 			last = err
 			log.Raw().WithError(err).Errorf("failed to clean up the namespace set of %s", digest)
 		}
+		if err := set.Release(set.template); err != nil {
+			last = err
+			log.Raw().WithError(err).Errorf("failed to release the template namespace of %s", digest)
+		}
 	}
 	return last
 }
@@ -338,7 +578,25 @@ var maskedPaths = []string{
 	"/proc/scsi",
 }
 
-func createBundle() (string, error) {
+// dirUsage returns the disk usage of the files under dir
+func dirUsage(dir string) int64 {
+	var usage int64
+	filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if st, ok := info.Sys().(*syscall.Stat_t); ok {
+			usage += st.Blocks * 512
+		} else {
+			usage += info.Size()
+		}
+		return nil
+	})
+	return usage
+}
+
+// createBundle creates a bundle owned by the root of owner
+func createBundle(owner bundleOwner) (string, error) {
 	// create the bundle dir
 	bundle, err := ioutil.TempDir("", ".cer.bundle.*")
 	if err != nil {
@@ -357,12 +615,21 @@ func createBundle() (string, error) {
 	if err = os.Mkdir(rootfsPath, 0711); err != nil {
 		return "", err
 	}
+	if owner.path == "" {
+		return bundle, nil
+	}
+	for _, dir := range []string{bundle, upperPath, workPath, rootfsPath} {
+		if err = os.Chown(dir, owner.uid, owner.gid); err != nil {
+			os.RemoveAll(bundle)
+			return "", err
+		}
+	}
 	return bundle, nil
 }
 
-func (mgr *mountManager) makeNewNamespaceCreator(rootfsPath, checkpointPath string) func() (*os.File, error) {
+func (mgr *mountManager) makeNewNamespaceCreator(rootfsPath, checkpointPath string, owner bundleOwner) func() (*os.File, error) {
 	return func() (*os.File, error) {
-		bundle, err := createBundle()
+		bundle, err := createBundle(owner)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to create bundle")
 		}
@@ -376,6 +643,7 @@ func (mgr *mountManager) makeNewNamespaceCreator(rootfsPath, checkpointPath stri
 				"checkpoint": checkpointPath,
 			},
 		)
+		helper.JoinUserNamespace(owner.path)
 		if err = helper.Do(false); err != nil {
 			return nil, errors.Wrap(err, "failed to execute the namespace helper")
 		}
@@ -384,7 +652,51 @@ func (mgr *mountManager) makeNewNamespaceCreator(rootfsPath, checkpointPath stri
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to open namespace file")
 		}
+		mgr.bundlesMu.Lock()
 		mgr.allBundles[int(newNSFile.Fd())] = bundle
+		mgr.bundlesMu.Unlock()
+		return newNSFile, nil
+	}
+}
+
+// makeCloneCreator returns a creator which clones the template namespace, the clone keeps the mounts
+// of the template on top of a new overlay, so it is much cheaper than populating a bundle from scratch.
+// The clone must be created in owner, the user namespace owning the template
+func (mgr *mountManager) makeCloneCreator(rootfsPath, checkpointPath string, template *os.File, owner bundleOwner) func() (*os.File, error) {
+	templateBundle := mgr.bundleOf(template)
+	return func() (*os.File, error) {
+		bundle, err := createBundle(owner)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create bundle")
+		}
+		helper, err := namespace.NewNamespaceExecCloneHelper(
+			namespace.NamespaceFunctionKeyClone,
+			types.NamespaceMNT,
+			fmt.Sprintf("/proc/%d/fd/%d", os.Getpid(), int(template.Fd())),
+			map[string]string{
+				"src":        rootfsPath,
+				"bundle":     bundle,
+				"checkpoint": checkpointPath,
+				"template":   templateBundle,
+			},
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create namespace helper")
+		}
+		helper.JoinUserNamespace(owner.path)
+		if err = helper.Do(false); err != nil {
+			// the mounts of the bundle are gone with the namespace of the helper
+			os.RemoveAll(bundle)
+			return nil, errors.Wrapf(err, "failed to clone the namespace of bundle %s", templateBundle)
+		}
+		defer helper.Release()
+		newNSFile, err := namespace.OpenNSFile(types.NamespaceMNT, helper.Cmd.Process.Pid)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to open namespace file")
+		}
+		mgr.bundlesMu.Lock()
+		mgr.allBundles[int(newNSFile.Fd())] = bundle
+		mgr.bundlesMu.Unlock()
 		return newNSFile, nil
 	}
 }
@@ -430,10 +742,8 @@ func populateRootfs(rootfs, checkpoint string) error {
 	return nil
 }
 
-func restoreExtraMountpoints(rootfs, checkpoint string) error {
-	const (
-		mountpointsPrefix = "mountpoints-"
-	)
+// baseMountpoints returns the mount points of the mounts made in every rootfs besides the ones of the checkpoint
+func baseMountpoints() map[string]struct{} {
 	mp := map[string]struct{}{
 		"/": {},
 	}
@@ -443,10 +753,18 @@ func restoreExtraMountpoints(rootfs, checkpoint string) error {
 	for _, m := range append(append([]string{}, readonlyPaths...), maskedPaths...) {
 		mp[m] = struct{}{}
 	}
+	return mp
+}
+
+// openMountpointsImage opens the image of the mount points in checkpoint
+func openMountpointsImage(checkpoint string) (*criuimages.Image, error) {
+	const (
+		mountpointsPrefix = "mountpoints-"
+	)
 	mpFilePath := ""
 	infos, err := ioutil.ReadDir(checkpoint)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	for _, info := range infos {
 		if strings.HasPrefix(info.Name(), mountpointsPrefix) {
@@ -455,11 +773,20 @@ func restoreExtraMountpoints(rootfs, checkpoint string) error {
 		}
 	}
 	if mpFilePath == "" {
-		return errors.New("failed to find mountpoints.img")
+		return nil, errors.New("failed to find mountpoints.img")
 	}
 	img, err := criuimages.New(mpFilePath)
 	if err != nil {
-		return errors.Wrapf(err, "failed to open image %s", mpFilePath)
+		return nil, errors.Wrapf(err, "failed to open image %s", mpFilePath)
+	}
+	return img, nil
+}
+
+func restoreExtraMountpoints(rootfs, checkpoint string) error {
+	mp := baseMountpoints()
+	img, err := openMountpointsImage(checkpoint)
+	if err != nil {
+		return err
 	}
 	defer img.Close()
 	entry := &criutype.MntEntry{}
@@ -622,18 +949,8 @@ func populateBundle(args map[string]interface{}) ([]byte, error) {
 func doPopulate(src, bundle, checkpoint string) error {
 	// mount the src dir to rootfs dir in bundle
 	rootfs := path.Join(bundle, "rootfs")
-	m := mount.Mount{
-		Source: "overlay",
-		Type:   "overlay",
-	}
-	m.SetWork(path.Join(bundle, "work"))
-	m.SetUpper(path.Join(bundle, "upper"))
-	m.SetLowers([]string{src})
-	if err := m.Mount(rootfs); err != nil {
-		return errors.Wrapf(err, "mount rootfs %s with overlay failed", rootfs)
-	}
-	if err := unix.Chmod(rootfs, 0755); err != nil {
-		return errors.Wrap(err, "can not chmod")
+	if err := mountOverlay(src, path.Join(bundle, "upper"), path.Join(bundle, "work"), rootfs); err != nil {
+		return err
 	}
 	return populateRootfs(rootfs, checkpoint)
 }
@@ -0,0 +1,284 @@
+package mnt
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/YLonely/cer-manager/mount"
+	criutype "github.com/YLonely/criuimages/types"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// PutMode decides what becomes of a mount namespace put back
+type PutMode string
+
+const (
+	// PutModeDestroy depopulates the bundle of the namespace and removes it, the set is refilled from scratch
+	PutModeDestroy PutMode = "destroy"
+	// PutModeRecycle resets the overlay upper dir and the tmpfs contents of the namespace and reuses it with its mounts
+	PutModeRecycle PutMode = "recycle"
+)
+
+// Valid reports whether the mode is known
+func (m PutMode) Valid() bool {
+	return m == PutModeDestroy || m == PutModeRecycle
+}
+
+var mountinfoUnescaper = strings.NewReplacer(`\040`, " ", `\011`, "\t", `\012`, "\n", `\134`, `\`)
+
+// resetBundle runs in the mount namespace put back, it swaps the overlay of the rootfs for a fresh one,
+// clears the tmpfs mounted by us and restores the files of the checkpoint
+func resetBundle(args map[string]interface{}) ([]byte, error) {
+	src, ok := args["src"].(string)
+	if !ok || src == "" {
+		return nil, errors.New("src must be provided")
+	}
+	bundle, ok := args["bundle"].(string)
+	if !ok || bundle == "" {
+		return nil, errors.New("bundle must be provided")
+	}
+	checkpoint, ok := args["checkpoint"].(string)
+	if !ok || checkpoint == "" {
+		return nil, errors.New("checkpoint must be provided")
+	}
+	if err := swapOverlay(src, bundle, checkpoint); err != nil {
+		return nil, errors.Wrap(err, "failed to swap the overlay")
+	}
+	rootfs := path.Join(bundle, "rootfs")
+	if err := clearTmpfs(rootfs); err != nil {
+		return nil, err
+	}
+	if err := restoreFiles(rootfs, checkpoint); err != nil {
+		return nil, errors.Wrap(err, "failed to restore files")
+	}
+	return nil, nil
+}
+
+// swapOverlay mounts a new overlay of src with empty upper and work dirs, moves the mounts made in the rootfs
+// of bundle for checkpoint onto it and puts it in place of the old one. The upper dir of an overlay can not be cleared
+// while it is mounted, so the old dirs are moved aside and removed once the old overlay is detached
+func swapOverlay(src, bundle, checkpoint string) error {
+	rootfs := path.Join(bundle, "rootfs")
+	next := path.Join(bundle, "rootfs.next")
+	upper, work := path.Join(bundle, "upper"), path.Join(bundle, "work")
+	oldUpper, oldWork := upper+".old", work+".old"
+	for _, dir := range [][2]string{{upper, oldUpper}, {work, oldWork}} {
+		if err := os.RemoveAll(dir[1]); err != nil {
+			return err
+		}
+		// the mounted overlay keeps using the dirs renamed
+		if err := os.Rename(dir[0], dir[1]); err != nil {
+			return err
+		}
+	}
+	for _, dir := range []string{next, upper, work} {
+		if err := reMakeDir(dir); err != nil {
+			return err
+		}
+	}
+	if err := mountOverlay(src, upper, work, next); err != nil {
+		return err
+	}
+	if err := moveSubmounts(rootfs, next, checkpoint); err != nil {
+		unix.Unmount(next, unix.MNT_DETACH)
+		return err
+	}
+	if err := unix.Unmount(rootfs, unix.MNT_DETACH); err != nil {
+		return errors.Wrap(err, "failed to unmount rootfs")
+	}
+	if err := unix.Mount(next, rootfs, "", unix.MS_MOVE, ""); err != nil {
+		return errors.Wrapf(err, "failed to move %s to %s", next, rootfs)
+	}
+	if err := os.Remove(next); err != nil {
+		return err
+	}
+	for _, dir := range []string{oldUpper, oldWork} {
+		if err := os.RemoveAll(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mountOverlay mounts the overlay of src with upper and work at rootfs
+func mountOverlay(src, upper, work, rootfs string) error {
+	m := mount.Mount{
+		Source: "overlay",
+		Type:   "overlay",
+	}
+	m.SetWork(work)
+	m.SetUpper(upper)
+	m.SetLowers([]string{src})
+	if err := m.Mount(rootfs); err != nil {
+		return errors.Wrapf(err, "mount rootfs %s with overlay failed", rootfs)
+	}
+	if err := unix.Chmod(rootfs, 0755); err != nil {
+		return errors.Wrap(err, "can not chmod")
+	}
+	return nil
+}
+
+// moveSubmounts moves the mounts in from, along with the ones on top of them, to the same paths in to.
+// Only the mounts we make for checkpoint are moved, it fails if any other mount is found in from
+func moveSubmounts(from, to, checkpoint string) error {
+	own, err := ownMountpoints(checkpoint)
+	if err != nil {
+		return err
+	}
+	subs, err := submounts(from, own)
+	if err != nil {
+		return err
+	}
+	for _, sub := range subs {
+		target := path.Join(to, strings.TrimPrefix(sub, from))
+		if err := unix.Mount(sub, target, "", unix.MS_MOVE, ""); err != nil {
+			return errors.Wrapf(err, "failed to move mount %s to %s", sub, target)
+		}
+	}
+	return nil
+}
+
+// ownMountpoints returns the mount points in the rootfs of the mounts populateRootfs makes for checkpoint
+func ownMountpoints(checkpoint string) (map[string]struct{}, error) {
+	mp := baseMountpoints()
+	img, err := openMountpointsImage(checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer img.Close()
+	for {
+		entry := &criutype.MntEntry{}
+		if err := img.ReadOne(entry); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, errors.Wrap(err, "failed to read entry")
+		}
+		if entry.GetExtKey() != "" {
+			mp[entry.GetMountpoint()] = struct{}{}
+		}
+	}
+	return mp, nil
+}
+
+// submounts returns the mount points of the mounts right on top of the mount at target,
+// each mount above target must be at one of the mount points in own relative to target
+func submounts(target string, own map[string]struct{}) ([]string, error) {
+	infos, err := readMountinfo("/proc/self/mountinfo")
+	if err != nil {
+		return nil, err
+	}
+	id := ""
+	if info := lastMountAt(infos, target); info != nil {
+		id = info.id
+	}
+	if id == "" {
+		return nil, errors.Errorf("%s is not a mount point", target)
+	}
+	var subs []string
+	above := map[string]struct{}{id: {}}
+	// a mount moved onto target may be listed before it, so walk the mounts until no more are found above target
+	for found := true; found; {
+		found = false
+		for _, info := range infos {
+			if _, ok := above[info.parent]; !ok {
+				continue
+			}
+			if _, ok := above[info.id]; ok {
+				continue
+			}
+			above[info.id] = struct{}{}
+			found = true
+			if !strings.HasPrefix(info.mountpoint, target+"/") {
+				return nil, errors.Errorf("mount at %s is not made by us", info.mountpoint)
+			}
+			if _, ok := own[strings.TrimPrefix(info.mountpoint, target)]; !ok {
+				return nil, errors.Errorf("mount at %s is not made by us", info.mountpoint)
+			}
+			if info.parent == id {
+				subs = append(subs, info.mountpoint)
+			}
+		}
+	}
+	return subs, nil
+}
+
+// mountinfo is a line of /proc/<pid>/mountinfo
+type mountinfo struct {
+	id, parent, mountpoint string
+	fstype                 string
+	// superOptions are the options of the super block
+	superOptions []string
+}
+
+func readMountinfo(file string) ([]mountinfo, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var infos []mountinfo
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 {
+			continue
+		}
+		info := mountinfo{
+			id:         fields[0],
+			parent:     fields[1],
+			mountpoint: mountinfoUnescaper.Replace(fields[4]),
+		}
+		// the optional fields end with a single hyphen
+		for i := 5; i < len(fields); i++ {
+			if fields[i] != "-" {
+				continue
+			}
+			if i+1 < len(fields) {
+				info.fstype = fields[i+1]
+			}
+			if i+3 < len(fields) {
+				info.superOptions = strings.Split(fields[i+3], ",")
+			}
+			break
+		}
+		infos = append(infos, info)
+	}
+	return infos, scanner.Err()
+}
+
+// lastMountAt returns the mount at target which hides the ones under it, nil if target is not a mount point
+func lastMountAt(infos []mountinfo, target string) *mountinfo {
+	var last *mountinfo
+	for i := range infos {
+		if infos[i].mountpoint == target {
+			last = &infos[i]
+		}
+	}
+	return last
+}
+
+// clearTmpfs removes everything in the tmpfs mounted in rootfs
+func clearTmpfs(rootfs string) error {
+	for _, m := range mounts {
+		if m.Type != "tmpfs" {
+			continue
+		}
+		dir := path.Join(rootfs, m.target)
+		infos, err := ioutil.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		for _, info := range infos {
+			if err = os.RemoveAll(path.Join(dir, info.Name())); err != nil {
+				return errors.Wrapf(err, "failed to clear %s", dir)
+			}
+		}
+	}
+	return nil
+}
@@ -37,6 +37,8 @@ const (
 	NamespaceFunctionKeyCreate  NamespaceFunctionKey = "create"
 	NamespaceFunctionKeyRelease NamespaceFunctionKey = "release"
 	NamespaceFunctionKeyReset   NamespaceFunctionKey = "reset"
+	NamespaceFunctionKeyClone   NamespaceFunctionKey = "clone"
+	NamespaceFunctionKeyCheck   NamespaceFunctionKey = "check"
 )
 
 const (
@@ -53,6 +55,15 @@ func OpenNSFile(t types.NamespaceType, pid int) (*os.File, error) {
 		nsFileName = "uts"
 	case types.NamespaceMNT:
 		nsFileName = "mnt"
+	case types.NamespaceNET:
+		nsFileName = "net"
+	case types.NamespaceCGROUP:
+		nsFileName = "cgroup"
+	case types.NamespaceUSER:
+		nsFileName = "user"
+	case types.NamespaceTIME:
+		// the creator of a time namespace stays out of it, only its children are in it
+		nsFileName = "time_for_children"
 	default:
 		return nil, errors.New("invalid ns type")
 	}
@@ -0,0 +1,270 @@
+package net
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"unsafe"
+
+	"github.com/YLonely/cer-manager/namespace"
+	"github.com/YLonely/cer-manager/utils"
+	"github.com/YLonely/criuimages"
+	criutype "github.com/YLonely/criuimages/types"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	netIPv4Conf = "net/ipv4/conf"
+	netIPv6Conf = "net/ipv6/conf"
+	netUnixConf = "net/unix"
+)
+
+// devconfs4 are the names of the ipv4 sysctls of a device in the order of conf4 in the netns image
+var devconfs4 = []string{
+	"accept_local",
+	"accept_redirects",
+	"accept_source_route",
+	"arp_accept",
+	"arp_announce",
+	"arp_filter",
+	"arp_ignore",
+	"arp_notify",
+	"bootp_relay",
+	"disable_policy",
+	"disable_xfrm",
+	"force_igmp_version",
+	"forwarding",
+	"igmpv2_unsolicited_report_interval",
+	"igmpv3_unsolicited_report_interval",
+	"log_martians",
+	"medium_id",
+	"promote_secondaries",
+	"proxy_arp",
+	"proxy_arp_pvlan",
+	"route_localnet",
+	"rp_filter",
+	"secure_redirects",
+	"send_redirects",
+	"shared_media",
+	"src_valid_mark",
+	"tag",
+	"ignore_routes_with_linkdown",
+	"drop_gratuitous_arp",
+	"drop_unicast_in_l2_multicast",
+}
+
+// devconfs6 are the names of the ipv6 sysctls of a device in the order of conf6 in the netns image
+var devconfs6 = []string{
+	"accept_dad",
+	"accept_ra",
+	"accept_ra_defrtr",
+	"accept_ra_from_local",
+	"accept_ra_min_hop_limit",
+	"accept_ra_mtu",
+	"accept_ra_pinfo",
+	"accept_ra_rt_info_max_plen",
+	"accept_ra_rtr_pref",
+	"accept_redirects",
+	"accept_source_route",
+	"autoconf",
+	"dad_transmits",
+	"disable_ipv6",
+	"drop_unicast_in_l2_multicast",
+	"drop_unsolicited_na",
+	"force_mld_version",
+	"force_tllao",
+	"forwarding",
+	"hop_limit",
+	"ignore_routes_with_linkdown",
+	"keep_addr_on_down",
+	"max_addresses",
+	"max_desync_factor",
+	"mldv1_unsolicited_report_interval",
+	"mldv2_unsolicited_report_interval",
+	"mtu",
+	"ndisc_notify",
+	"optimistic_dad",
+	"proxy_ndp",
+	"regen_max_retry",
+	"router_probe_interval",
+	"router_solicitation_delay",
+	"router_solicitation_interval",
+	"router_solicitations",
+	"stable_secret",
+	"suppress_frag_ndisc",
+	"temp_prefered_lft",
+	"temp_valid_lft",
+	"use_oif_addrs_only",
+	"use_optimistic",
+	"use_tempaddr",
+}
+
+// unixConfs are the names of the unix socket sysctls in the order of unix_conf in the netns image
+var unixConfs = []string{
+	"max_dgram_qlen",
+}
+
+// populateNamespace runs in a new network namespace, it brings the loopback up and applies the sysctls
+// in the netns image of the checkpoint
+func populateNamespace(args map[string]interface{}) ([]byte, error) {
+	cp, ok := args["checkpoint"].(string)
+	if !ok || cp == "" {
+		return nil, errors.New("checkpoint must be provided")
+	}
+	if err := setLinkUp("lo"); err != nil {
+		return nil, errors.Wrap(err, "failed to bring up loopback")
+	}
+	entry, err := checkpointedNetns(cp)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+	conf4, conf6 := entry.GetDefConf4(), entry.GetDefConf6()
+	all4, all6 := entry.GetAllConf4(), entry.GetAllConf6()
+	// the images of old versions only have the ipv4 sysctls as integers
+	if len(conf4) == 0 {
+		conf4 = intSysctls(entry.GetDefConf())
+	}
+	if len(all4) == 0 {
+		all4 = intSysctls(entry.GetAllConf())
+	}
+	// the sysctls of all are applied first as they may change the default ones
+	confs := []struct {
+		dir     string
+		names   []string
+		entries []*criutype.SysctlEntry
+	}{
+		{dir: path.Join(netIPv4Conf, "all"), names: devconfs4, entries: all4},
+		{dir: path.Join(netIPv4Conf, "default"), names: devconfs4, entries: conf4},
+		{dir: path.Join(netIPv6Conf, "all"), names: devconfs6, entries: all6},
+		{dir: path.Join(netIPv6Conf, "default"), names: devconfs6, entries: conf6},
+		{dir: netUnixConf, names: unixConfs, entries: entry.GetUnixConf()},
+	}
+	for _, conf := range confs {
+		if err := applySysctls(conf.dir, conf.names, conf.entries); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// checkpointedNetns returns the entry in the netns image of the checkpoint, nil if the image does not exist
+func checkpointedNetns(checkpoint string) (*criutype.NetnsEntry, error) {
+	images, err := filepath.Glob(filepath.Join(checkpoint, "netns-*.img"))
+	if err != nil {
+		return nil, err
+	}
+	if len(images) == 0 {
+		return nil, nil
+	}
+	// criuimages does not know the magic of the netns image
+	entry := &criutype.NetnsEntry{}
+	if err = namespace.ReadImageEntry(images[0], criuimages.NETNS_MAGIC, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func intSysctls(values []int32) []*criutype.SysctlEntry {
+	entries := make([]*criutype.SysctlEntry, 0, len(values))
+	for i := range values {
+		t := criutype.SysctlType_CTL_32
+		entries = append(entries, &criutype.SysctlEntry{Type: &t, Iarg: &values[i]})
+	}
+	return entries
+}
+
+// applySysctls writes the sysctls in entries to the files of names under dir,
+// the ones missing in the image or in the kernel and the ones unchanged are skipped
+func applySysctls(dir string, names []string, entries []*criutype.SysctlEntry) error {
+	for i, entry := range entries {
+		if i >= len(names) {
+			break
+		}
+		var value string
+		switch {
+		case entry.GetType() == criutype.SysctlType_CTL_STR && entry.Sarg != nil:
+			value = entry.GetSarg()
+		case entry.GetType() == criutype.SysctlType_CTL_32 && entry.Iarg != nil:
+			value = strconv.Itoa(int(entry.GetIarg()))
+		default:
+			continue
+		}
+		item := path.Join(dir, names[i])
+		current, err := utils.SysCtlRead(item)
+		if err != nil && os.IsNotExist(errors.Cause(err)) {
+			continue
+		}
+		if err == nil && current == value {
+			continue
+		}
+		if err = utils.SysCtlWrite(item, value); err != nil {
+			return errors.Wrapf(err, "failed to set %s to %s", item, value)
+		}
+	}
+	return nil
+}
+
+// ifreqFlags is struct ifreq with the flags in the union
+type ifreqFlags struct {
+	name  [unix.IFNAMSIZ]byte
+	flags uint16
+	_     [22]byte
+}
+
+// setLinkUp brings up the link of name in the network namespace we are in
+func setLinkUp(name string) error {
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(fd)
+	req := ifreqFlags{}
+	copy(req.name[:], name)
+	if err = ioctl(fd, unix.SIOCGIFFLAGS, &req); err != nil {
+		return errors.Wrapf(err, "failed to get the flags of %s", name)
+	}
+	req.flags |= unix.IFF_UP
+	if err = ioctl(fd, unix.SIOCSIFFLAGS, &req); err != nil {
+		return errors.Wrapf(err, "failed to set the flags of %s", name)
+	}
+	return nil
+}
+
+// linkUp reports whether the link of name in the network namespace we are in is up
+func linkUp(name string) (bool, error) {
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return false, err
+	}
+	defer unix.Close(fd)
+	req := ifreqFlags{}
+	copy(req.name[:], name)
+	if err = ioctl(fd, unix.SIOCGIFFLAGS, &req); err != nil {
+		return false, errors.Wrapf(err, "failed to get the flags of %s", name)
+	}
+	return req.flags&unix.IFF_UP != 0, nil
+}
+
+func ioctl(fd int, req uint, ifr *ifreqFlags) error {
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), uintptr(req), uintptr(unsafe.Pointer(ifr))); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// checkNamespace runs in an idle network namespace, it checks that the loopback is still up
+func checkNamespace(map[string]interface{}) ([]byte, error) {
+	up, err := linkUp("lo")
+	if err != nil {
+		return nil, err
+	}
+	if !up {
+		return nil, errors.New("loopback is down")
+	}
+	return nil, nil
+}
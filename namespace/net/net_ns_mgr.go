@@ -0,0 +1,83 @@
+package net
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/YLonely/cer-manager/api/types"
+	"github.com/YLonely/cer-manager/namespace"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	namespace.PutNamespaceFunction(namespace.NamespaceFunctionKeyCreate, types.NamespaceNET, populateNamespace)
+	namespace.PutNamespaceFunction(namespace.NamespaceFunctionKeyCheck, types.NamespaceNET, checkNamespace)
+}
+
+// NewManager returns the manager of the network namespaces of refs
+func NewManager(capacities []int, refs []types.Reference, supplier types.Supplier, refiller *namespace.Refiller, budget *namespace.Budget) (namespace.Manager, error) {
+	m, err := namespace.NewPoolManager(namespace.PoolConfig{
+		Type:     types.NamespaceNET,
+		Refiller: refiller,
+		Budget:   budget,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, ref := range refs {
+		cp, err := supplier.Get(ref)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get checkpoint path for %s", ref)
+		}
+		err = m.Add(ref, namespace.Pool{
+			Capacity: capacities[i],
+			Cost:     types.Resources{Namespaces: 1},
+			Create:   makeNETNamespaceCreator(cp),
+			Check:    checkNETNamespace,
+			// the borrower may have added links and routes, so the namespace is never reused
+			Put: namespace.PutRelease,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func makeNETNamespaceCreator(checkpointPath string) func() (*os.File, error) {
+	return func() (*os.File, error) {
+		h, err := namespace.NewNamespaceExecCreateHelper(
+			namespace.NamespaceFunctionKeyCreate,
+			types.NamespaceNET,
+			map[string]string{
+				"checkpoint": checkpointPath,
+			},
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create net create helper")
+		}
+		if err = h.Do(false); err != nil {
+			return nil, errors.Wrap(err, "failed to create new NET namespace")
+		}
+		defer h.Release()
+		nsFile, err := namespace.OpenNSFile(types.NamespaceNET, h.Cmd.Process.Pid)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to open NET namespace")
+		}
+		return nsFile, nil
+	}
+}
+
+// checkNETNamespace enters the namespace f and checks it
+func checkNETNamespace(f *os.File) error {
+	h, err := namespace.NewNamespaceExecEnterHelper(
+		namespace.NamespaceFunctionKeyCheck,
+		types.NamespaceNET,
+		fmt.Sprintf("/proc/%d/fd/%d", os.Getpid(), int(f.Fd())),
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+	return h.Do(true)
+}
@@ -0,0 +1,71 @@
+package namespace
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/YLonely/cer-manager/api/types"
+)
+
+// NewOwners returns an empty Owners
+func NewOwners() *Owners {
+	return &Owners{
+		owners: map[string]map[types.NamespaceType]owner{},
+	}
+}
+
+// Owners maps the references to the user namespaces owning their namespaces of each type,
+// the managers create the namespaces of a reference in its owner if there is one
+type Owners struct {
+	m      sync.Mutex
+	owners map[string]map[types.NamespaceType]owner
+}
+
+type owner struct {
+	f *os.File
+	// uid and gid are the ids of the host the root of the user namespace is mapped to
+	uid, gid int
+}
+
+// Set makes the user namespace f the owner of the namespaces of ts of ref,
+// the root of f is mapped to uid and gid of the host
+func (o *Owners) Set(ref types.Reference, ts []types.NamespaceType, f *os.File, uid, gid int) {
+	o.m.Lock()
+	defer o.m.Unlock()
+	owners, exists := o.owners[ref.Digest()]
+	if !exists {
+		owners = map[types.NamespaceType]owner{}
+		o.owners[ref.Digest()] = owners
+	}
+	for _, t := range ts {
+		owners[t] = owner{f: f, uid: uid, gid: gid}
+	}
+}
+
+// Path returns the path to the user namespace owning the namespaces of type t of ref,
+// it is empty if o is nil or they are owned by the user namespace of the daemon
+func (o *Owners) Path(ref types.Reference, t types.NamespaceType) string {
+	if o == nil {
+		return ""
+	}
+	o.m.Lock()
+	defer o.m.Unlock()
+	owner, exists := o.owners[ref.Digest()][t]
+	if !exists {
+		return ""
+	}
+	return fmt.Sprintf("/proc/%d/fd/%d", os.Getpid(), int(owner.f.Fd()))
+}
+
+// Root returns the ids of the host the root of the user namespace owning the namespaces of type t of ref
+// is mapped to, they are 0 if o is nil or the namespaces are owned by the user namespace of the daemon
+func (o *Owners) Root(ref types.Reference, t types.NamespaceType) (uid, gid int) {
+	if o == nil {
+		return 0, 0
+	}
+	o.m.Lock()
+	defer o.m.Unlock()
+	owner := o.owners[ref.Digest()][t]
+	return owner.uid, owner.gid
+}
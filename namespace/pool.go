@@ -0,0 +1,385 @@
+package namespace
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	apiservices "github.com/YLonely/cer-manager/api/services"
+	"github.com/YLonely/cer-manager/api/types"
+	"github.com/YLonely/cer-manager/log"
+	"github.com/pkg/errors"
+)
+
+// PutPolicy decides what becomes of the namespaces put back to a PoolManager
+type PutPolicy int
+
+const (
+	// PutRelease destroys the namespaces put back, it is for the namespaces whose changes can not be undone
+	PutRelease PutPolicy = iota
+	// PutRecycle resets the namespaces put back and returns them to their pool
+	PutRecycle
+)
+
+// Pool is how the namespaces of a reference in a PoolManager are created, checked and put back
+type Pool struct {
+	Capacity int
+	// Cost is the resources charged to the budget for each namespace
+	Cost   types.Resources
+	Create func() (*os.File, error)
+	// Release is called before a namespace is destroyed, it may be nil
+	Release func(*os.File) error
+	// Check checks an idle namespace, the namespaces are not checked if it is nil
+	Check func(*os.File) error
+	Put   PutPolicy
+	// Reset resets a namespace put back in the background before it is recycled,
+	// the namespaces are recycled as they are if it is nil
+	Reset func(*os.File) error
+	// Info returns the info of a namespace checked out, it may be nil
+	Info func(*os.File) interface{}
+	// Members are the references the namespaces are got for, it is empty if they are got for the reference of the pool alone
+	Members []types.Reference
+	// Shared makes the pool hold a single namespace lent to all the borrowers, it is never resized or put back
+	Shared bool
+}
+
+// PoolConfig configures a PoolManager
+type PoolConfig struct {
+	Type types.NamespaceType
+	// Select picks the reference whose namespace is got for refs, the first of them is the one asked for.
+	// It may take an idle namespace of the reference picked from its set. Get with extra references
+	// is not supported if it is nil
+	Select func(refs []types.Reference) (target types.Reference, f *os.File, reason string, err error)
+	// CheckInterval is the interval of the health checks, HealthCheckInterval is used if it is zero
+	CheckInterval time.Duration
+	Refiller      *Refiller
+	Budget        *Budget
+}
+
+// NewPoolManager returns a PoolManager without any pool, the pools are added by Add
+func NewPoolManager(config PoolConfig) (*PoolManager, error) {
+	handles, err := NewHandleIssuer(config.Type)
+	if err != nil {
+		return nil, err
+	}
+	m := &PoolManager{
+		config:  config,
+		pools:   map[string]*pool{},
+		used:    map[string]usedNamespace{},
+		handles: handles,
+	}
+	m.LeaseKeeper = NewLeaseKeeper(config.Type, m.expire)
+	m.autoscaler = NewAutoscaler(config.Type, m.resize)
+	interval := config.CheckInterval
+	if interval == 0 {
+		interval = HealthCheckInterval
+	}
+	m.health = NewHealthChecker(config.Type, interval)
+	return m, nil
+}
+
+var _ Manager = &PoolManager{}
+
+// PoolManager is the Manager keeping a pool of namespaces for each reference,
+// the type packages supply how the namespaces of each pool are created, checked and put back
+type PoolManager struct {
+	*LeaseKeeper
+	config PoolConfig
+	m      sync.Mutex
+	// pools maps the digest of a reference to its pool
+	pools map[string]*pool
+	// used maps a handle to the namespace checked out
+	used       map[string]usedNamespace
+	handles    *HandleIssuer
+	autoscaler *Autoscaler
+	health     *HealthChecker
+}
+
+type pool struct {
+	Pool
+	set *Set
+	// shared is the namespace of a shared pool, set is nil then
+	shared *os.File
+}
+
+type usedNamespace struct {
+	ref types.Reference
+	f   *os.File
+}
+
+// Add creates the pool of ref and fills it up to its capacity
+func (m *PoolManager) Add(ref types.Reference, p Pool) error {
+	if p.Shared {
+		return m.addShared(ref, p)
+	}
+	release := p.Release
+	if release == nil {
+		release = func(*os.File) error { return nil }
+	}
+	meter := m.config.Budget.Meter(p.Cost, nil)
+	set, err := NewSet(p.Capacity, meter.Creator(p.Create), meter.Releaser(release))
+	if err != nil {
+		return errors.Wrapf(err, "failed to create namespace set for ref %s", ref)
+	}
+	set.SetAdmission(meter.Admit)
+	m.m.Lock()
+	defer m.m.Unlock()
+	if _, exists := m.pools[ref.Digest()]; exists {
+		set.CleanUp()
+		return errors.Errorf("%s namespaces of ref %s exist", m.name(), ref)
+	}
+	m.config.Refiller.Watch(m.config.Type, ref, set)
+	if p.Check != nil {
+		m.health.Watch(ref, set, p.Check)
+	}
+	m.pools[ref.Digest()] = &pool{Pool: p, set: set}
+	return nil
+}
+
+// addShared creates the namespace of the shared pool of ref, it is charged to the budget until the manager is cleaned up
+func (m *PoolManager) addShared(ref types.Reference, p Pool) error {
+	if err := m.config.Budget.Acquire(p.Cost); err != nil {
+		return errors.Wrapf(err, "failed to create the shared %s namespace of %s", m.name(), ref)
+	}
+	f, err := p.Create()
+	if err != nil {
+		m.config.Budget.Release(p.Cost)
+		return errors.Wrapf(err, "failed to create the shared %s namespace of %s", m.name(), ref)
+	}
+	m.m.Lock()
+	defer m.m.Unlock()
+	if _, exists := m.pools[ref.Digest()]; exists {
+		m.releaseShared(p, f)
+		return errors.Errorf("%s namespaces of ref %s exist", m.name(), ref)
+	}
+	m.pools[ref.Digest()] = &pool{Pool: p, shared: f}
+	return nil
+}
+
+// releaseShared destroys the namespace f of the shared pool p
+func (m *PoolManager) releaseShared(p Pool, f *os.File) error {
+	var err error
+	if p.Release != nil {
+		err = p.Release(f)
+	}
+	f.Close()
+	m.config.Budget.Release(p.Cost)
+	return err
+}
+
+// Shared returns the namespace of the shared pool of ref, or nil if the pool of ref is not shared
+func (m *PoolManager) Shared(ref types.Reference) *os.File {
+	m.m.Lock()
+	defer m.m.Unlock()
+	if p, exists := m.pools[ref.Digest()]; exists {
+		return p.shared
+	}
+	return nil
+}
+
+// SetOf returns the set of the pool of ref, or nil if ref is not pooled or its pool is shared
+func (m *PoolManager) SetOf(ref types.Reference) *Set {
+	m.m.Lock()
+	defer m.m.Unlock()
+	if p, exists := m.pools[ref.Digest()]; exists {
+		return p.set
+	}
+	return nil
+}
+
+// name is the type of the namespaces in messages
+func (m *PoolManager) name() string {
+	return strings.ToUpper(string(m.config.Type))
+}
+
+func (m *PoolManager) Get(ref types.Reference, wait time.Duration, extraRefs ...types.Reference) (handle string, f *os.File, info interface{}, err error) {
+	target, reason := ref, ""
+	if len(extraRefs) != 0 {
+		if m.config.Select == nil {
+			err = apiservices.Errorf(apiservices.CodeInvalidArgument, "extra references is not supported")
+			return
+		}
+		if target, f, reason, err = m.config.Select(append([]types.Reference{ref}, extraRefs...)); err != nil {
+			return
+		}
+	}
+	m.m.Lock()
+	p, exists := m.pools[target.Digest()]
+	m.m.Unlock()
+	if !exists {
+		err = apiservices.Errorf(apiservices.CodeNotFound, "%s namespaces of ref %s does not exist", m.name(), target)
+		return
+	}
+	if f == nil && p.shared != nil {
+		f = p.shared
+	} else if f == nil {
+		// wait without holding the lock, the namespace is handed over by Put or the refiller
+		f = p.set.Wait(wait)
+	}
+	if f == nil {
+		m.autoscaler.Miss(target)
+		err = ErrUsedUp(m.config.Type, target, wait)
+		return
+	}
+	m.m.Lock()
+	defer m.m.Unlock()
+	if handle, err = m.handles.Next(); err != nil {
+		if p.shared == nil {
+			p.set.Add(f)
+		}
+		f = nil
+		return
+	}
+	m.autoscaler.Checkout(handle, target)
+	m.used[handle] = usedNamespace{ref: target, f: f}
+	if p.Info != nil {
+		info = p.Info(f)
+	}
+	if len(extraRefs) != 0 {
+		info = types.Selection{Ref: target, Reason: reason, Info: info}
+	}
+	return
+}
+
+func (m *PoolManager) Put(handle string) error {
+	m.m.Lock()
+	defer m.m.Unlock()
+	return m.put(handle)
+}
+
+func (m *PoolManager) put(handle string) error {
+	item, exists := m.used[handle]
+	if !exists {
+		return m.handles.Unknown(handle)
+	}
+	p, exists := m.pools[item.ref.Digest()]
+	if !exists {
+		panic(errors.Errorf("namespace set of ref %s does not exist", item.ref))
+	}
+	delete(m.used, handle)
+	m.Revoke(handle)
+	m.autoscaler.Return(handle)
+	switch {
+	case p.shared != nil:
+	case p.Put == PutRelease:
+		p.set.Release(item.f)
+	case p.Reset == nil:
+		p.set.Recycle(item.f)
+	default:
+		go func() {
+			if err := p.Reset(item.f); err != nil {
+				log.Raw().WithError(err).Errorf("failed to reset %s namespace %s of %s, discard it", m.name(), handle, item.ref)
+				p.set.Release(item.f)
+				return
+			}
+			p.set.Recycle(item.f)
+		}()
+	}
+	return nil
+}
+
+func (m *PoolManager) Lease(handle string, ttl time.Duration) (types.Lease, error) {
+	m.m.Lock()
+	defer m.m.Unlock()
+	item, exists := m.used[handle]
+	if !exists {
+		return types.Lease{}, m.handles.Unknown(handle)
+	}
+	return m.Grant(handle, item.ref, ttl)
+}
+
+// Refs returns the reference of the namespace checked out with handle, or the members of its pool
+func (m *PoolManager) Refs(handle string) ([]types.Reference, error) {
+	m.m.Lock()
+	defer m.m.Unlock()
+	item, exists := m.used[handle]
+	if !exists {
+		return nil, m.handles.Unknown(handle)
+	}
+	if members := m.pools[item.ref.Digest()].Members; len(members) != 0 {
+		return members, nil
+	}
+	return []types.Reference{item.ref}, nil
+}
+
+// expire puts back the namespace whose lease expired
+func (m *PoolManager) expire(handle string, id string) {
+	m.m.Lock()
+	defer m.m.Unlock()
+	if !m.Take(handle, id) {
+		return
+	}
+	if err := m.put(handle); err != nil {
+		log.Raw().WithError(err).Errorf("failed to put back %s namespace %s with expired lease", m.name(), handle)
+	}
+}
+
+// Update resizes the pool of ref, the references not pooled and the shared pools are skipped
+func (m *PoolManager) Update(ref types.Reference, capacity int) error {
+	m.m.Lock()
+	defer m.m.Unlock()
+	p, exists := m.pools[ref.Digest()]
+	if !exists || p.shared != nil {
+		return nil
+	}
+	if m.autoscaler.Enabled(ref) {
+		return ErrAutoscaled(m.config.Type, ref)
+	}
+	return p.set.Update(capacity)
+}
+
+// resize is called by the autoscaler, the namespaces are created by the refiller instead of under the lock
+func (m *PoolManager) resize(ref types.Reference, capacity int) error {
+	set := m.SetOf(ref)
+	if set == nil {
+		return apiservices.Errorf(apiservices.CodeNotFound, "%s namespaces of ref %s does not exist", m.name(), ref)
+	}
+	return set.Resize(capacity)
+}
+
+// Autoscale enables the autoscaler of the pool of ref, the references not pooled and the shared pools are skipped
+func (m *PoolManager) Autoscale(ref types.Reference, min, max int) error {
+	m.m.Lock()
+	defer m.m.Unlock()
+	p, exists := m.pools[ref.Digest()]
+	if !exists || p.shared != nil {
+		return nil
+	}
+	return m.autoscaler.Enable(ref, p.set.Target(), min, max)
+}
+
+func (m *PoolManager) Health() []types.HealthStatus {
+	return m.health.Status()
+}
+
+func (m *PoolManager) CleanUp() error {
+	m.Stop()
+	m.autoscaler.Stop()
+	m.health.Stop()
+	m.m.Lock()
+	defer m.m.Unlock()
+	var last error
+	for digest, p := range m.pools {
+		if p.shared != nil {
+			if err := m.releaseShared(p.Pool, p.shared); err != nil {
+				last = err
+				log.Raw().WithError(err).Errorf("failed to release the shared %s namespace of %s", m.name(), digest)
+			}
+			continue
+		}
+		if err := p.set.CleanUp(); err != nil {
+			last = err
+			log.Raw().WithError(err).Errorf("failed to clean up the %s namespace set of %s", m.name(), digest)
+		}
+	}
+	for _, item := range m.used {
+		p := m.pools[item.ref.Digest()]
+		if p.shared != nil {
+			continue
+		}
+		log.Raw().Warnf("%s namespace %d of %s is being used", m.name(), item.f.Fd(), item.ref)
+		p.set.Release(item.f)
+	}
+	return last
+}
@@ -0,0 +1,196 @@
+package namespace
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	apiservices "github.com/YLonely/cer-manager/api/services"
+	"github.com/YLonely/cer-manager/api/types"
+)
+
+// newTestPoolManager returns a PoolManager whose refiller has no workers
+func newTestPoolManager(t *testing.T, config PoolConfig) *PoolManager {
+	config.Type = types.NamespaceUTS
+	config.Refiller = NewRefiller(0, 0.5)
+	config.Budget = NewBudget(types.Resources{})
+	m, err := NewPoolManager(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		m.CleanUp()
+		config.Refiller.Stop()
+	})
+	return m
+}
+
+func openNullPool(capacity int, put PutPolicy) Pool {
+	return Pool{
+		Capacity: capacity,
+		Cost:     types.Resources{Namespaces: 1},
+		Create:   func() (*os.File, error) { return os.Open(os.DevNull) },
+		Put:      put,
+	}
+}
+
+func TestPoolManagerPut(t *testing.T) {
+	ref := types.Reference{Name: "test"}
+	failed := errors.New("injected failure")
+	tests := []struct {
+		name  string
+		put   PutPolicy
+		reset func(*os.File) error
+		// want is the number of idle namespaces once the namespace is put back
+		want int
+	}{
+		{name: "release", put: PutRelease, want: 0},
+		{name: "recycle as is", put: PutRecycle, want: 1},
+		{name: "recycle after reset", put: PutRecycle, reset: func(*os.File) error { return nil }, want: 1},
+		{name: "reset fails", put: PutRecycle, reset: func(*os.File) error { return failed }, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestPoolManager(t, PoolConfig{})
+			reset := make(chan struct{}, 1)
+			p := openNullPool(1, tt.put)
+			if tt.reset != nil {
+				p.Reset = func(f *os.File) error {
+					defer func() { reset <- struct{}{} }()
+					return tt.reset(f)
+				}
+			}
+			if err := m.Add(ref, p); err != nil {
+				t.Fatal(err)
+			}
+			handle, _, _, err := m.Get(ref, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = m.Put(handle); err != nil {
+				t.Fatal(err)
+			}
+			if tt.reset != nil {
+				<-reset
+			}
+			waitFor(t, func() bool { return m.SetOf(ref).Capacity() == tt.want })
+			if err = m.Put(handle); !errors.Is(err, apiservices.ErrNotFound) {
+				t.Fatalf("Put() of a stale handle returned %v", err)
+			}
+		})
+	}
+}
+
+func TestPoolManagerGet(t *testing.T) {
+	a, b := types.Reference{Name: "a"}, types.Reference{Name: "b"}
+	tests := []struct {
+		name  string
+		pick  func(refs []types.Reference) (types.Reference, *os.File, string, error)
+		extra []types.Reference
+		want  types.Reference
+		// err is the error expected, nil if the Get succeeds
+		err error
+	}{
+		{name: "no extra references", want: a},
+		{name: "extra references not supported", extra: []types.Reference{b}, err: apiservices.ErrInvalidArgument},
+		{
+			name: "selected",
+			pick: func(refs []types.Reference) (types.Reference, *os.File, string, error) {
+				return refs[1], nil, "picked", nil
+			},
+			extra: []types.Reference{b},
+			want:  b,
+		},
+		{
+			name: "selected without a pool",
+			pick: func(refs []types.Reference) (types.Reference, *os.File, string, error) {
+				return types.Reference{Name: "c"}, nil, "picked", nil
+			},
+			extra: []types.Reference{b},
+			err:   apiservices.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestPoolManager(t, PoolConfig{Select: tt.pick})
+			for _, ref := range []types.Reference{a, b} {
+				if err := m.Add(ref, openNullPool(1, PutRelease)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			handle, _, info, err := m.Get(a, time.Millisecond, tt.extra...)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("Get() returned %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			refs, err := m.Refs(handle)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(refs) != 1 || refs[0].Digest() != tt.want.Digest() {
+				t.Fatalf("got a namespace of %v, want %s", refs, tt.want)
+			}
+			if selection, ok := info.(types.Selection); len(tt.extra) != 0 && (!ok || selection.Ref.Digest() != tt.want.Digest()) {
+				t.Fatalf("Get() returned info %v", info)
+			}
+		})
+	}
+}
+
+func TestPoolManagerShared(t *testing.T) {
+	ref := types.Reference{Name: "test"}
+	m := newTestPoolManager(t, PoolConfig{})
+	p := openNullPool(0, PutRelease)
+	p.Shared = true
+	if err := m.Add(ref, p); err != nil {
+		t.Fatal(err)
+	}
+	shared := m.Shared(ref)
+	if shared == nil || m.SetOf(ref) != nil {
+		t.Fatal("the shared pool holds no namespace or has a set")
+	}
+	for i := 0; i < 2; i++ {
+		handle, f, _, err := m.Get(ref, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f != shared {
+			t.Fatal("Get() returned a namespace other than the shared one")
+		}
+		if err = m.Put(handle); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := shared.Stat(); err != nil {
+		t.Fatalf("the shared namespace is released on Put: %v", err)
+	}
+	if err := m.Update(ref, 3); err != nil {
+		t.Fatal(err)
+	}
+	if status := m.config.Budget.Status(); status.Used.Namespaces != 1 {
+		t.Fatalf("%d namespaces are charged to the budget, want 1", status.Used.Namespaces)
+	}
+}
+
+func TestPoolManagerUpdateAutoscaled(t *testing.T) {
+	ref := types.Reference{Name: "test"}
+	m := newTestPoolManager(t, PoolConfig{})
+	if err := m.Add(ref, openNullPool(1, PutRelease)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Update(ref, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Autoscale(ref, 1, 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Update(ref, 2); !errors.Is(err, apiservices.ErrInvalidArgument) {
+		t.Fatalf("Update() of an autoscaled ref returned %v", err)
+	}
+}
@@ -0,0 +1,217 @@
+package namespace
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/YLonely/cer-manager/api/types"
+	"github.com/YLonely/cer-manager/log"
+)
+
+// the backoff of a failing set doubles from refillBackoffBase up to refillBackoffMax
+var (
+	refillBackoffBase = time.Second
+	refillBackoffMax  = time.Minute
+)
+
+// NewRefiller returns a Refiller with workers workers and starts them. A set is refilled once the namespaces
+// in it fall below the low watermark, which is lowWatermark times its target capacity, and up to the target capacity
+func NewRefiller(workers int, lowWatermark float64) *Refiller {
+	r := &Refiller{
+		workers:      workers,
+		lowWatermark: lowWatermark,
+		jobs:         map[*Set]*refillJob{},
+		priorities:   map[string]int{},
+	}
+	r.cond = sync.NewCond(&r.m)
+	for i := 0; i < workers; i++ {
+		go r.work()
+	}
+	return r
+}
+
+// Refiller refills the namespace sets of all the managers with a bounded number of workers.
+// The sets with callers waiting are refilled first, then the sets of the references with higher priority,
+// a set failing to create namespaces is retried with an exponential backoff.
+type Refiller struct {
+	m            sync.Mutex
+	cond         *sync.Cond
+	workers      int
+	lowWatermark float64
+	// queue holds the jobs ready to run or backing off
+	queue []*refillJob
+	// jobs maps each set being refilled to its job, the job is kept until the set is full
+	jobs map[*Set]*refillJob
+	// priorities maps the digest of a reference to its priority
+	priorities map[string]int
+	running    int
+	seq        uint64
+	stopped    bool
+}
+
+type refillJob struct {
+	t        types.NamespaceType
+	ref      types.Reference
+	set      *Set
+	seq      uint64
+	failures int
+	// notBefore is the time the job is allowed to run after a failure
+	notBefore time.Time
+}
+
+// Watch lets the refiller refill set, which holds the namespaces of type t of ref
+func (r *Refiller) Watch(t types.NamespaceType, ref types.Reference, set *Set) {
+	set.notify(func() { r.Schedule(t, ref, set) })
+}
+
+// SetPriority sets the priority of the sets of ref, the priority is 0 by default
+func (r *Refiller) SetPriority(ref types.Reference, priority int) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.priorities[ref.Digest()] = priority
+}
+
+// Schedule queues set for refilling if it falls below the low watermark or someone is waiting on it
+func (r *Refiller) Schedule(t types.NamespaceType, ref types.Reference, set *Set) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.stopped {
+		return
+	}
+	if _, exists := r.jobs[set]; exists {
+		return
+	}
+	low := int(math.Ceil(float64(set.Target()) * r.lowWatermark))
+	if set.Capacity() >= low && set.Waiters() == 0 {
+		return
+	}
+	r.seq++
+	j := &refillJob{
+		t:   t,
+		ref: ref,
+		set: set,
+		seq: r.seq,
+	}
+	r.jobs[set] = j
+	r.queue = append(r.queue, j)
+	r.cond.Signal()
+}
+
+// Status returns the state of the refiller
+func (r *Refiller) Status() types.RefillStatus {
+	r.m.Lock()
+	defer r.m.Unlock()
+	status := types.RefillStatus{
+		Workers: r.workers,
+		Running: r.running,
+	}
+	now := time.Now()
+	for _, j := range r.queue {
+		if now.Before(j.notBefore) {
+			status.BackingOff++
+		} else {
+			status.Queued++
+		}
+	}
+	return status
+}
+
+// Stop stops the workers after they finish the namespaces being created
+func (r *Refiller) Stop() {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.stopped = true
+	r.queue = nil
+	r.jobs = map[*Set]*refillJob{}
+	r.cond.Broadcast()
+}
+
+func (r *Refiller) work() {
+	for {
+		r.m.Lock()
+		j := r.next()
+		for j == nil && !r.stopped {
+			r.cond.Wait()
+			j = r.next()
+		}
+		if r.stopped {
+			r.m.Unlock()
+			return
+		}
+		r.running++
+		r.m.Unlock()
+
+		err := j.set.CreateOne()
+
+		r.m.Lock()
+		r.running--
+		if _, exists := r.jobs[j.set]; !exists {
+			// the refiller is stopped
+			r.m.Unlock()
+			continue
+		}
+		if err != nil {
+			j.failures++
+			backoff := refillBackoffBase << uint(j.failures-1)
+			if backoff > refillBackoffMax || backoff <= 0 {
+				backoff = refillBackoffMax
+			}
+			j.notBefore = time.Now().Add(backoff)
+			log.Raw().WithError(err).Errorf("failed to create a new %s namespace for %s, retry in %s", j.t, j.ref, backoff)
+			r.queue = append(r.queue, j)
+			time.AfterFunc(backoff, r.wake)
+			r.m.Unlock()
+			continue
+		}
+		j.failures = 0
+		if j.set.NeedsRefill() {
+			// queue it again behind the others, so a large set does not hold up the workers
+			r.seq++
+			j.seq = r.seq
+			r.queue = append(r.queue, j)
+			r.cond.Signal()
+		} else {
+			delete(r.jobs, j.set)
+		}
+		r.m.Unlock()
+	}
+}
+
+func (r *Refiller) wake() {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.cond.Broadcast()
+}
+
+// next removes and returns the job to run first, nil if no job is ready
+func (r *Refiller) next() *refillJob {
+	now := time.Now()
+	best := -1
+	for i, j := range r.queue {
+		if now.Before(j.notBefore) {
+			continue
+		}
+		if best < 0 || r.before(j, r.queue[best]) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return nil
+	}
+	j := r.queue[best]
+	r.queue = append(r.queue[:best], r.queue[best+1:]...)
+	return j
+}
+
+func (r *Refiller) before(a, b *refillJob) bool {
+	aw, bw := a.set.Waiters() > 0, b.set.Waiters() > 0
+	if aw != bw {
+		return aw
+	}
+	ap, bp := r.priorities[a.ref.Digest()], r.priorities[b.ref.Digest()]
+	if ap != bp {
+		return ap > bp
+	}
+	return a.seq < b.seq
+}
@@ -0,0 +1,90 @@
+package namespace
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/YLonely/cer-manager/api/types"
+)
+
+func TestRefillerBackoff(t *testing.T) {
+	defer func(base, max time.Duration) {
+		refillBackoffBase, refillBackoffMax = base, max
+	}(refillBackoffBase, refillBackoffMax)
+	refillBackoffBase, refillBackoffMax = 20*time.Millisecond, 50*time.Millisecond
+	const failures = 4
+	var (
+		m     sync.Mutex
+		calls []time.Time
+	)
+	s, err := NewSet(0, func() (*os.File, error) {
+		m.Lock()
+		defer m.Unlock()
+		calls = append(calls, time.Now())
+		if len(calls) <= failures {
+			return nil, errors.New("injected failure")
+		}
+		return os.Open(os.DevNull)
+	}, func(*os.File) error { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.CleanUp()
+	r := NewRefiller(2, 1)
+	defer r.Stop()
+	r.Watch(types.NamespaceUTS, types.Reference{Name: "test"}, s)
+	s.SetTarget(1)
+	waitFor(t, func() bool { return r.Status().BackingOff == 1 })
+	if f := s.Wait(2 * time.Second); f == nil {
+		t.Fatal("the set is not refilled after the failures")
+	} else {
+		f.Close()
+	}
+	m.Lock()
+	defer m.Unlock()
+	// the set is refilled again once the namespace is handed to us
+	if len(calls) < failures+1 {
+		t.Fatalf("the namespace is created %d times, want at least %d", len(calls), failures+1)
+	}
+	// the backoff doubles after each failure up to the max
+	want := []time.Duration{20 * time.Millisecond, 40 * time.Millisecond, 50 * time.Millisecond, 50 * time.Millisecond}
+	for i, backoff := range want {
+		if got := calls[i+1].Sub(calls[i]); got < backoff {
+			t.Errorf("retry %d after %v, want at least %v", i+1, got, backoff)
+		}
+	}
+}
+
+func TestRefillerWatermark(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		target   int
+		waiters  int
+		want     bool
+	}{
+		{name: "above the watermark", capacity: 3, target: 4, want: false},
+		{name: "below the watermark", capacity: 1, target: 4, want: true},
+		{name: "someone waiting", capacity: 0, target: 0, waiters: 1, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSet(t, tt.capacity)
+			s.target = tt.target
+			for i := 0; i < tt.waiters; i++ {
+				s.waiters = append(s.waiters, make(chan *os.File, 1))
+			}
+			// no workers, the job stays queued
+			r := NewRefiller(0, 0.5)
+			defer r.Stop()
+			r.Schedule(types.NamespaceUTS, types.Reference{Name: "test"}, s)
+			if got := r.Status().Queued == 1; got != tt.want {
+				t.Fatalf("the set is queued %v, want %v", got, tt.want)
+			}
+			s.waiters = nil
+		})
+	}
+}
@@ -2,6 +2,8 @@ package namespace
 
 import (
 	"os"
+	"sync"
+	"time"
 )
 
 func NewSet(capacity int, namespaceCreator func() (*os.File, error), preReleaseNamespace func(*os.File) error) (*Set, error) {
@@ -15,28 +17,89 @@ func NewSet(capacity int, namespaceCreator func() (*os.File, error), preReleaseN
 	}
 	return &Set{
 		defaultCapacity:     capacity,
+		target:              capacity,
 		files:               files,
 		namespaceCreator:    namespaceCreator,
 		preReleaseNamespace: preReleaseNamespace,
 	}, nil
 }
 
+// Set is a pool of namespace files, it is safe for concurrent use
 type Set struct {
-	defaultCapacity     int
-	files               map[int]*os.File
+	mu              sync.Mutex
+	defaultCapacity int
+	// target is the capacity set by the last Update
+	target int
+	files  map[int]*os.File
+	// waiters are the callers blocked in Wait in arrival order,
+	// a namespace added to the set is handed to the first one directly
+	waiters []chan *os.File
+	closed  bool
+	// onTake is called after a namespace is taken from the set or a caller starts waiting
+	onTake func()
+	// admit checks if n more namespaces can be created for the set
+	admit               func(n int) error
 	namespaceCreator    func() (*os.File, error)
 	preReleaseNamespace func(*os.File) error
 }
 
-func (s Set) Capacity() int {
+func (s *Set) Capacity() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return len(s.files)
 }
 
-func (s Set) DefaultCapacity() int {
+func (s *Set) DefaultCapacity() int {
 	return s.defaultCapacity
 }
 
+// Target returns the capacity the set is expected to keep
+func (s *Set) Target() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.target
+}
+
+// NeedsRefill reports whether the set holds fewer namespaces than its target or someone is waiting for one
+func (s *Set) NeedsRefill() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !s.closed && (len(s.files) < s.target || len(s.waiters) > 0)
+}
+
+// Waiters returns the number of callers waiting for a namespace
+func (s *Set) Waiters() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.waiters)
+}
+
+// Get returns a namespace file or nil if the set is empty
 func (s *Set) Get() *os.File {
+	s.mu.Lock()
+	f := s.get()
+	s.mu.Unlock()
+	s.taken()
+	return f
+}
+
+func (s *Set) notify(f func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onTake = f
+}
+
+// taken should be called without holding the lock, a set cleaned up is not refilled
+func (s *Set) taken() {
+	s.mu.Lock()
+	f, closed := s.onTake, s.closed
+	s.mu.Unlock()
+	if f != nil && !closed {
+		f()
+	}
+}
+
+func (s *Set) get() *os.File {
 	for _, f := range s.files {
 		ret := f
 		delete(s.files, int(ret.Fd()))
@@ -45,7 +108,73 @@ func (s *Set) Get() *os.File {
 	return nil
 }
 
+// Wait is like Get but blocks up to timeout for a namespace to be added if the set is empty.
+// Callers are served in arrival order, nil is returned if the timeout expires or the set is cleaned up
+func (s *Set) Wait(timeout time.Duration) *os.File {
+	s.mu.Lock()
+	if f := s.get(); f != nil || timeout <= 0 || s.closed {
+		s.mu.Unlock()
+		s.taken()
+		return f
+	}
+	ch := make(chan *os.File, 1)
+	s.waiters = append(s.waiters, ch)
+	s.mu.Unlock()
+	s.taken()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case f := <-ch:
+		return f
+	case <-timer.C:
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, w := range s.waiters {
+		if w == ch {
+			s.waiters = append(s.waiters[:i], s.waiters[i+1:]...)
+			return nil
+		}
+	}
+	// a namespace was handed to us right before the timeout
+	return <-ch
+}
+
+// Idle returns the namespaces in the set, they are still owned by the set
+func (s *Set) Idle() []*os.File {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	files := make([]*os.File, 0, len(s.files))
+	for _, f := range s.files {
+		files = append(files, f)
+	}
+	return files
+}
+
+// Evict takes f out of the set if it is still in it, the caller releases f then.
+// The set is refilled as if f is taken by Get
+func (s *Set) Evict(f *os.File) bool {
+	s.mu.Lock()
+	fd := int(f.Fd())
+	evicted := s.files[fd] == f
+	if evicted {
+		delete(s.files, fd)
+	}
+	s.mu.Unlock()
+	if evicted {
+		s.taken()
+	}
+	return evicted
+}
+
 func (s *Set) CleanUp() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for _, w := range s.waiters {
+		close(w)
+	}
+	s.waiters = nil
 	var last error
 	for fd, f := range s.files {
 		if err := s.preReleaseNamespace(f); err != nil {
@@ -57,6 +186,22 @@ func (s *Set) CleanUp() error {
 	return last
 }
 
+// SetAdmission sets admit to check if Update can grow the set by n namespaces
+func (s *Set) SetAdmission(admit func(n int) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.admit = admit
+}
+
+// Release destroys f which is taken from the set. The set is refilled as if f is taken by Get,
+// so a namespace discarded after its checkout does not leave the set short
+func (s *Set) Release(f *os.File) error {
+	err := s.preReleaseNamespace(f)
+	f.Close()
+	s.taken()
+	return err
+}
+
 func (s *Set) CreateOne() error {
 	f, err := s.namespaceCreator()
 	if err != nil {
@@ -66,13 +211,77 @@ func (s *Set) CreateOne() error {
 	return nil
 }
 
+// Add puts f into the set, or hands it to the longest waiting caller of Wait.
+// f is released if the set is cleaned up
 func (s *Set) Add(f *os.File) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		s.preReleaseNamespace(f)
+		f.Close()
+		return
+	}
+	if len(s.waiters) > 0 {
+		w := s.waiters[0]
+		s.waiters = s.waiters[1:]
+		w <- f
+		return
+	}
 	s.files[int(f.Fd())] = f
 }
 
+// Recycle puts f taken from the set back, f is released instead if the set holds enough namespaces already
+func (s *Set) Recycle(f *os.File) {
+	s.mu.Lock()
+	full := len(s.waiters) == 0 && len(s.files) >= s.target
+	s.mu.Unlock()
+	if full {
+		s.Release(f)
+		return
+	}
+	s.Add(f)
+}
+
+// SetTarget is like Update but leaves the creation of the namespaces to the refiller watching the set
+func (s *Set) SetTarget(capacity int) {
+	s.mu.Lock()
+	s.target = capacity
+	s.mu.Unlock()
+	s.taken()
+}
+
+// Resize is like SetTarget but releases the idle namespaces beyond capacity at once
+func (s *Set) Resize(capacity int) error {
+	s.mu.Lock()
+	s.target = capacity
+	var excess []*os.File
+	for len(s.files) > capacity {
+		excess = append(excess, s.get())
+	}
+	s.mu.Unlock()
+	s.taken()
+	var last error
+	for _, f := range excess {
+		if err := s.Release(f); err != nil {
+			last = err
+		}
+	}
+	return last
+}
+
 func (s *Set) Update(capacity int) error {
-	cap := s.Capacity()
+	s.mu.Lock()
+	cap, admit := len(s.files), s.admit
+	s.mu.Unlock()
 	diff := capacity - cap
+	if diff > 0 && admit != nil {
+		if err := admit(diff); err != nil {
+			return err
+		}
+	}
+	s.mu.Lock()
+	s.target = capacity
+	s.mu.Unlock()
 	if diff == 0 {
 		return nil
 	}
@@ -89,8 +298,10 @@ func (s *Set) Update(capacity int) error {
 		}
 		for i := 0; i < diff; i++ {
 			f := s.Get()
-			defer f.Close()
-			if err := s.preReleaseNamespace(f); err != nil {
+			if f == nil {
+				break
+			}
+			if err := s.Release(f); err != nil {
 				return err
 			}
 		}
@@ -0,0 +1,220 @@
+package namespace
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestSet returns a set of capacity whose namespaces are files opened on /dev/null
+func newTestSet(t *testing.T, capacity int) *Set {
+	s, err := NewSet(capacity, func() (*os.File, error) {
+		return os.Open(os.DevNull)
+	}, func(*os.File) error { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.CleanUp() })
+	return s
+}
+
+func openNull(t *testing.T) *os.File {
+	f, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// waitFor polls cond until it holds or a second passes
+func waitFor(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSetWait(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		timeout  time.Duration
+		cleanUp  bool
+		want     bool
+	}{
+		{name: "idle namespace", capacity: 1, timeout: 0, want: true},
+		{name: "empty without waiting", capacity: 0, timeout: 0, want: false},
+		{name: "empty until timeout", capacity: 0, timeout: 10 * time.Millisecond, want: false},
+		{name: "cleaned up", capacity: 0, timeout: time.Hour, cleanUp: true, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSet(t, tt.capacity)
+			if tt.cleanUp {
+				go func() {
+					for s.Waiters() == 0 {
+						time.Sleep(time.Millisecond)
+					}
+					s.CleanUp()
+				}()
+			}
+			f := s.Wait(tt.timeout)
+			if got := f != nil; got != tt.want {
+				t.Fatalf("Wait() returned a namespace %v, want %v", got, tt.want)
+			}
+			if s.Waiters() != 0 {
+				t.Fatalf("%d waiters are left", s.Waiters())
+			}
+		})
+	}
+}
+
+func TestSetWaitFIFO(t *testing.T) {
+	const n = 5
+	s := newTestSet(t, 0)
+	got := make(chan int, n)
+	for i := 0; i < n; i++ {
+		i := i
+		go func() {
+			if f := s.Wait(time.Minute); f != nil {
+				got <- i
+			}
+		}()
+		// start the next one only after this one is queued
+		waitFor(t, func() bool { return s.Waiters() == i+1 })
+	}
+	for i := 0; i < n; i++ {
+		s.Add(openNull(t))
+		if w := <-got; w != i {
+			t.Fatalf("namespace %d is handed to waiter %d", i, w)
+		}
+	}
+	if c := s.Capacity(); c != 0 {
+		t.Fatalf("%d namespaces are left in the set", c)
+	}
+}
+
+// TestSetWaitTimeoutRace hands a namespace over right when the timeout of the waiter fires,
+// the namespace must end up either with the waiter or back in the set
+func TestSetWaitTimeoutRace(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		s := newTestSet(t, 0)
+		timeout := time.Duration(i%4) * 100 * time.Microsecond
+		got := make(chan *os.File)
+		go func() { got <- s.Wait(timeout) }()
+		time.Sleep(timeout)
+		s.Add(openNull(t))
+		f := <-got
+		held := 0
+		if f != nil {
+			held = 1
+		}
+		if held+s.Capacity() != 1 {
+			t.Fatalf("round %d: the waiter holds %d and the set %d namespaces, want 1 in total", i, held, s.Capacity())
+		}
+		if s.Waiters() != 0 {
+			t.Fatalf("round %d: %d waiters are left", i, s.Waiters())
+		}
+	}
+}
+
+func TestSetConcurrentWaitAdd(t *testing.T) {
+	const n = 50
+	s := newTestSet(t, 0)
+	var (
+		wg  sync.WaitGroup
+		m   sync.Mutex
+		got int
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(2)
+		go func(timeout time.Duration) {
+			defer wg.Done()
+			if f := s.Wait(timeout); f != nil {
+				m.Lock()
+				got++
+				m.Unlock()
+			}
+		}(time.Duration(i%3) * time.Millisecond)
+		go func(f *os.File) {
+			defer wg.Done()
+			s.Add(f)
+		}(openNull(t))
+	}
+	wg.Wait()
+	if got+s.Capacity() != n {
+		t.Fatalf("%d namespaces are handed out and %d are idle, want %d in total", got, s.Capacity(), n)
+	}
+}
+
+func TestSetResize(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		resize   int
+		// want is the number of idle namespaces right after Resize, the refiller creates the rest
+		want int
+	}{
+		{name: "grow", capacity: 1, resize: 3, want: 1},
+		{name: "shrink", capacity: 3, resize: 1, want: 1},
+		{name: "shrink to zero", capacity: 2, resize: 0, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSet(t, tt.capacity)
+			if err := s.Resize(tt.resize); err != nil {
+				t.Fatal(err)
+			}
+			if s.Capacity() != tt.want || s.Target() != tt.resize {
+				t.Fatalf("the set holds %d namespaces with target %d, want %d with target %d", s.Capacity(), s.Target(), tt.want, tt.resize)
+			}
+			if got := s.NeedsRefill(); got != (tt.want < tt.resize) {
+				t.Fatalf("NeedsRefill() = %v", got)
+			}
+		})
+	}
+}
+
+func TestSetReleaseNotifies(t *testing.T) {
+	s := newTestSet(t, 1)
+	notified := 0
+	s.notify(func() { notified++ })
+	f := s.Get()
+	if err := s.Release(f); err != nil {
+		t.Fatal(err)
+	}
+	// once for the Get and once for the namespace discarded
+	if notified != 2 {
+		t.Fatalf("the refiller is notified %d times, want 2", notified)
+	}
+}
+
+func TestSetUpdateShrink(t *testing.T) {
+	var released []*os.File
+	s, err := NewSet(3, func() (*os.File, error) {
+		return os.Open(os.DevNull)
+	}, func(f *os.File) error {
+		released = append(released, f)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.CleanUp()
+	if err = s.Update(1); err != nil {
+		t.Fatal(err)
+	}
+	if len(released) != 2 || s.Capacity() != 1 {
+		t.Fatalf("%d namespaces are released and %d are left, want 2 and 1", len(released), s.Capacity())
+	}
+	for _, f := range released {
+		if _, err := f.Stat(); !errors.Is(err, os.ErrClosed) {
+			t.Fatalf("namespace released by Update is not closed: %v", err)
+		}
+	}
+}
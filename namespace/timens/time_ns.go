@@ -0,0 +1,82 @@
+package timens
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/YLonely/cer-manager/namespace"
+	"github.com/YLonely/criuimages"
+	criutype "github.com/YLonely/criuimages/types"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+const nsecPerSec = int64(1e9)
+
+// populateNamespace runs in the creator of a new time namespace, it sets the offsets of the namespace
+// so the clocks in it go on from the ones in the timens image of the checkpoint
+func populateNamespace(args map[string]interface{}) ([]byte, error) {
+	cp, ok := args["checkpoint"].(string)
+	if !ok || cp == "" {
+		return nil, errors.New("checkpoint must be provided")
+	}
+	entry, err := checkpointedTimens(cp)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+	clocks := []struct {
+		id     int32
+		dumped *criutype.Timespec
+	}{
+		{id: unix.CLOCK_MONOTONIC, dumped: entry.GetMonotonic()},
+		{id: unix.CLOCK_BOOTTIME, dumped: entry.GetBoottime()},
+	}
+	var lines []string
+	for _, clock := range clocks {
+		if clock.dumped == nil {
+			continue
+		}
+		var now unix.Timespec
+		if err := unix.ClockGettime(clock.id, &now); err != nil {
+			return nil, errors.Wrapf(err, "failed to get clock %d", clock.id)
+		}
+		offset := int64(clock.dumped.GetTvSec())*nsecPerSec + int64(clock.dumped.GetTvNsec()) - now.Nano()
+		sec, nsec := offset/nsecPerSec, offset%nsecPerSec
+		// the nanoseconds of an offset are never negative
+		if nsec < 0 {
+			sec, nsec = sec-1, nsec+nsecPerSec
+		}
+		// the clocks are named by their ids as the names are not known by the kernels before 5.11
+		lines = append(lines, fmt.Sprintf("%d %d %d\n", clock.id, sec, nsec))
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	// the offsets can only be written in one go before any process enters the namespace
+	if err := ioutil.WriteFile("/proc/self/timens_offsets", []byte(strings.Join(lines, "")), 0644); err != nil {
+		return nil, errors.Wrap(err, "failed to set the offsets of the clocks")
+	}
+	return nil, nil
+}
+
+// checkpointedTimens returns the entry in the timens image of the checkpoint, nil if the image does not exist
+func checkpointedTimens(checkpoint string) (*criutype.TimensEntry, error) {
+	images, err := filepath.Glob(filepath.Join(checkpoint, "timens-*.img"))
+	if err != nil {
+		return nil, err
+	}
+	if len(images) == 0 {
+		return nil, nil
+	}
+	// criuimages does not know the magic of the timens image
+	entry := &criutype.TimensEntry{}
+	if err = namespace.ReadImageEntry(images[0], criuimages.TIMENS_MAGIC, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
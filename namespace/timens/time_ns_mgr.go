@@ -0,0 +1,141 @@
+package timens
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/YLonely/cer-manager/api/types"
+	"github.com/YLonely/cer-manager/namespace"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	namespace.PutNamespaceFunction(namespace.NamespaceFunctionKeyCreate, types.NamespaceTIME, populateNamespace)
+}
+
+// ErrNotSupported is returned by NewManager if the kernel has no time namespaces
+var ErrNotSupported = errors.New("time namespaces are not supported by the kernel")
+
+const (
+	// MaxIdle is how long a time namespace stays in a set before it is replaced. The offsets of a namespace
+	// are set once it is created, so the clocks of a container restored in it jump ahead by the time it was idle
+	MaxIdle = 10 * time.Second
+	// checkInterval is the interval of the health checks replacing the namespaces idle for too long,
+	// a namespace is replaced at most MaxIdle plus checkInterval after it is created
+	checkInterval = MaxIdle / 2
+)
+
+// Info is the info of a time namespace checked out
+type Info struct {
+	// Drift is how far the clocks in the namespace are ahead of the checkpoint, which is the time since its offsets are set
+	Drift time.Duration `json:"drift"`
+}
+
+// NewManager returns the manager of the time namespaces of refs
+func NewManager(capacities []int, refs []types.Reference, supplier types.Supplier, refiller *namespace.Refiller, budget *namespace.Budget) (namespace.Manager, error) {
+	if _, err := os.Stat("/proc/self/ns/time"); os.IsNotExist(err) {
+		return nil, ErrNotSupported
+	}
+	pools, err := namespace.NewPoolManager(namespace.PoolConfig{
+		Type:          types.NamespaceTIME,
+		CheckInterval: checkInterval,
+		Refiller:      refiller,
+		Budget:        budget,
+	})
+	if err != nil {
+		return nil, err
+	}
+	m := &manager{
+		PoolManager: pools,
+		created:     map[*os.File]time.Time{},
+	}
+	for i, ref := range refs {
+		cp, err := supplier.Get(ref)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get checkpoint path for %s", ref)
+		}
+		err = m.Add(ref, namespace.Pool{
+			Capacity: capacities[i],
+			Cost:     types.Resources{Namespaces: 1},
+			Create:   m.makeTIMENamespaceCreator(cp),
+			Release:  m.forget,
+			Check:    m.check,
+			// the offsets can not be changed once a process is in the namespace, so it is never reused
+			Put:  namespace.PutRelease,
+			Info: m.info,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+type manager struct {
+	*namespace.PoolManager
+	// created maps the namespace to the time its offsets are set, the entry is dropped once the namespace
+	// is released so a new namespace never gets a stale one. It is guarded by createdMu as the namespaces
+	// are created by the refiller
+	created   map[*os.File]time.Time
+	createdMu sync.Mutex
+}
+
+// info returns the drift of the namespace f checked out
+func (m *manager) info(f *os.File) interface{} {
+	m.createdMu.Lock()
+	defer m.createdMu.Unlock()
+	if created, exists := m.created[f]; exists {
+		return Info{Drift: time.Since(created)}
+	}
+	return nil
+}
+
+// check reports the idle namespace f as unhealthy once its clocks are too far ahead of the checkpoint
+func (m *manager) check(f *os.File) error {
+	m.createdMu.Lock()
+	created, exists := m.created[f]
+	m.createdMu.Unlock()
+	if !exists {
+		return nil
+	}
+	if idle := time.Since(created); idle > MaxIdle {
+		return errors.Errorf("clocks are %s ahead of the checkpoint", idle.Round(time.Second))
+	}
+	return nil
+}
+
+// forget drops the creation time of the namespace f being released
+func (m *manager) forget(f *os.File) error {
+	m.createdMu.Lock()
+	delete(m.created, f)
+	m.createdMu.Unlock()
+	return nil
+}
+
+func (m *manager) makeTIMENamespaceCreator(checkpointPath string) func() (*os.File, error) {
+	return func() (*os.File, error) {
+		h, err := namespace.NewNamespaceExecCreateHelper(
+			namespace.NamespaceFunctionKeyCreate,
+			types.NamespaceTIME,
+			map[string]string{
+				"checkpoint": checkpointPath,
+			},
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create time create helper")
+		}
+		if err = h.Do(false); err != nil {
+			return nil, errors.Wrap(err, "failed to create new TIME namespace")
+		}
+		defer h.Release()
+		nsFile, err := namespace.OpenNSFile(types.NamespaceTIME, h.Cmd.Process.Pid)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to open TIME namespace")
+		}
+		m.createdMu.Lock()
+		m.created[nsFile] = time.Now()
+		m.createdMu.Unlock()
+		return nsFile, nil
+	}
+}
@@ -0,0 +1,119 @@
+package user
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/YLonely/cer-manager/api/types"
+	"github.com/pkg/errors"
+)
+
+// maxMappings is the max number of lines of uid_map and gid_map since linux 4.15
+const maxMappings = 340
+
+// IDMap maps Size ids starting from ContainerID in the user namespace to the ones starting from HostID outside of it
+type IDMap struct {
+	ContainerID uint32 `json:"container_id"`
+	HostID      uint32 `json:"host_id"`
+	Size        uint32 `json:"size"`
+}
+
+// SetGroups is the setgroups policy of a user namespace
+type SetGroups string
+
+const (
+	// SetGroupsAllow lets the processes in the user namespace call setgroups
+	SetGroupsAllow SetGroups = "allow"
+	// SetGroupsDeny forbids the processes in the user namespace to call setgroups
+	SetGroupsDeny SetGroups = "deny"
+)
+
+// Config configures the user namespaces of a reference
+type Config struct {
+	UIDMappings []IDMap `json:"uid_mappings"`
+	GIDMappings []IDMap `json:"gid_mappings"`
+	// SetGroups is left as the kernel default if it is empty
+	SetGroups SetGroups `json:"setgroups,omitempty"`
+	// ParentOf are the types of the namespaces of the reference created in a user namespace shared by its containers
+	ParentOf []types.NamespaceType `json:"parent_of,omitempty"`
+}
+
+// Validate checks the config
+func (c *Config) Validate() error {
+	for name, mappings := range map[string][]IDMap{"uid": c.UIDMappings, "gid": c.GIDMappings} {
+		if len(mappings) == 0 {
+			return errors.Errorf("no %s mappings", name)
+		}
+		if len(mappings) > maxMappings {
+			return errors.Errorf("more than %d %s mappings", maxMappings, name)
+		}
+		for _, m := range mappings {
+			if m.Size == 0 {
+				return errors.Errorf("empty %s mapping %d:%d", name, m.ContainerID, m.HostID)
+			}
+		}
+	}
+	if c.SetGroups != "" && c.SetGroups != SetGroupsAllow && c.SetGroups != SetGroupsDeny {
+		return errors.Errorf("invalid setgroups policy %q", c.SetGroups)
+	}
+	for _, t := range c.ParentOf {
+		if t != types.NamespaceIPC && t != types.NamespaceUTS && t != types.NamespaceMNT {
+			return errors.Errorf("%s namespaces can not be created in a shared user namespace", t)
+		}
+	}
+	// the helpers creating the namespaces in the shared user namespace run as its root
+	if _, _, ok := c.Root(); len(c.ParentOf) != 0 && !ok {
+		return errors.New("a shared user namespace must map the uid and gid 0")
+	}
+	return nil
+}
+
+// Root returns the ids of the host the uid and gid 0 of the user namespace are mapped to,
+// ok is false if either of them is not mapped
+func (c *Config) Root() (uid, gid int, ok bool) {
+	uid, uidOK := hostID(c.UIDMappings, 0)
+	gid, gidOK := hostID(c.GIDMappings, 0)
+	return uid, gid, uidOK && gidOK
+}
+
+// hostID returns the id of the host the id in the user namespace is mapped to by mappings
+func hostID(mappings []IDMap, id uint32) (int, bool) {
+	for _, m := range mappings {
+		if id >= m.ContainerID && id-m.ContainerID < m.Size {
+			return int(m.HostID + id - m.ContainerID), true
+		}
+	}
+	return 0, false
+}
+
+// writeMappings writes the mappings and the setgroups policy of c to the user namespace of the process pid,
+// setgroups must be written before gid_map
+func writeMappings(pid int, c *Config) error {
+	if err := writeProcFile(pid, "uid_map", formatMappings(c.UIDMappings)); err != nil {
+		return err
+	}
+	if c.SetGroups != "" {
+		if err := writeProcFile(pid, "setgroups", []byte(c.SetGroups)); err != nil {
+			return err
+		}
+	}
+	return writeProcFile(pid, "gid_map", formatMappings(c.GIDMappings))
+}
+
+// formatMappings returns the content of uid_map or gid_map, which must be written at once
+func formatMappings(mappings []IDMap) []byte {
+	var buf bytes.Buffer
+	for _, m := range mappings {
+		fmt.Fprintf(&buf, "%d %d %d\n", m.ContainerID, m.HostID, m.Size)
+	}
+	return buf.Bytes()
+}
+
+func writeProcFile(pid int, name string, content []byte) error {
+	file := fmt.Sprintf("/proc/%d/%s", pid, name)
+	if err := ioutil.WriteFile(file, content, 0644); err != nil {
+		return errors.Wrapf(err, "failed to write %s", file)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package user
+
+import (
+	"os"
+
+	"github.com/YLonely/cer-manager/api/types"
+	"github.com/YLonely/cer-manager/namespace"
+	"github.com/pkg/errors"
+)
+
+// Info is the info of a user namespace checked out
+type Info struct {
+	// Shared is true if the namespace is the one owning the other namespaces of the reference,
+	// it is shared by all the borrowers of the reference
+	Shared bool `json:"shared"`
+}
+
+// NewManager returns the manager of the user namespaces of refs, the namespaces of each reference are mapped
+// as its config in configs, the references without a config are not served. The shared user namespaces
+// of the references are recorded in owners
+func NewManager(capacities []int, refs []types.Reference, configs []*Config, refiller *namespace.Refiller, budget *namespace.Budget, owners *namespace.Owners) (namespace.Manager, error) {
+	m, err := namespace.NewPoolManager(namespace.PoolConfig{
+		Type:     types.NamespaceUSER,
+		Refiller: refiller,
+		Budget:   budget,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, ref := range refs {
+		if configs[i] == nil {
+			continue
+		}
+		// the namespace owning the namespaces of the types in ParentOf is shared by all the borrowers of ref
+		shared := len(configs[i].ParentOf) != 0
+		err := m.Add(ref, namespace.Pool{
+			Capacity: capacities[i],
+			Cost:     types.Resources{Namespaces: 1},
+			Create:   makeUserNamespaceCreator(configs[i]),
+			// the borrower may have left keys in the keyrings of the namespace, so it is never reused
+			Put:    namespace.PutRelease,
+			Info:   func(*os.File) interface{} { return Info{Shared: shared} },
+			Shared: shared,
+		})
+		if err != nil {
+			m.CleanUp()
+			return nil, err
+		}
+		if shared {
+			uid, gid, _ := configs[i].Root()
+			owners.Set(ref, configs[i].ParentOf, m.Shared(ref), uid, gid)
+		}
+	}
+	return m, nil
+}
+
+func makeUserNamespaceCreator(config *Config) func() (*os.File, error) {
+	return func() (*os.File, error) {
+		h, err := namespace.NewNamespaceExecCreateHelper(namespace.NamespaceFunctionKeyCreate, types.NamespaceUSER, nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create user create helper")
+		}
+		if err = h.Do(false); err != nil {
+			return nil, errors.Wrap(err, "failed to create new USER namespace")
+		}
+		defer h.Release()
+		// the mappings can only be written from outside once the helper is in the namespace
+		if err = writeMappings(h.Cmd.Process.Pid, config); err != nil {
+			return nil, errors.Wrap(err, "failed to map USER namespace")
+		}
+		nsFile, err := namespace.OpenNSFile(types.NamespaceUSER, h.Cmd.Process.Pid)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to open USER namespace")
+		}
+		return nsFile, nil
+	}
+}
@@ -0,0 +1,68 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/YLonely/cer-manager/api/types"
+)
+
+func TestConfigValidate(t *testing.T) {
+	root := []IDMap{{ContainerID: 0, HostID: 0, Size: 65536}}
+	rootless := []IDMap{{ContainerID: 0, HostID: 100000, Size: 65536}}
+	tests := []struct {
+		name   string
+		config Config
+		ok     bool
+	}{
+		{name: "pool", config: Config{UIDMappings: rootless, GIDMappings: rootless}, ok: true},
+		{name: "no mappings", config: Config{GIDMappings: rootless}, ok: false},
+		{name: "empty mapping", config: Config{UIDMappings: []IDMap{{HostID: 1000}}, GIDMappings: rootless}, ok: false},
+		{name: "invalid setgroups", config: Config{UIDMappings: rootless, GIDMappings: rootless, SetGroups: "maybe"}, ok: false},
+		{
+			name:   "rootless ipc and uts",
+			config: Config{UIDMappings: rootless, GIDMappings: rootless, ParentOf: []types.NamespaceType{types.NamespaceIPC, types.NamespaceUTS}},
+			ok:     true,
+		},
+		{
+			name:   "rootless mnt",
+			config: Config{UIDMappings: rootless, GIDMappings: rootless, ParentOf: []types.NamespaceType{types.NamespaceMNT}},
+			ok:     true,
+		},
+		{
+			name: "shared without the gid 0",
+			config: Config{
+				UIDMappings: rootless,
+				GIDMappings: []IDMap{{ContainerID: 1, HostID: 100001, Size: 65535}},
+				ParentOf:    []types.NamespaceType{types.NamespaceUTS},
+			},
+			ok: false,
+		},
+		{
+			name:   "pool without the uid 0",
+			config: Config{UIDMappings: []IDMap{{ContainerID: 1000, HostID: 1000, Size: 1}}, GIDMappings: rootless},
+			ok:     true,
+		},
+		{
+			name:   "net",
+			config: Config{UIDMappings: root, GIDMappings: root, ParentOf: []types.NamespaceType{types.NamespaceNET}},
+			ok:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); (err == nil) != tt.ok {
+				t.Fatalf("Validate() = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestConfigRoot(t *testing.T) {
+	c := Config{
+		UIDMappings: []IDMap{{ContainerID: 1, HostID: 200001, Size: 65535}, {ContainerID: 0, HostID: 1000, Size: 1}},
+		GIDMappings: []IDMap{{ContainerID: 0, HostID: 100000, Size: 65536}},
+	}
+	if uid, gid, ok := c.Root(); !ok || uid != 1000 || gid != 100000 {
+		t.Fatalf("Root() = %d, %d, %v, want 1000, 100000, true", uid, gid, ok)
+	}
+}
@@ -1,25 +1,43 @@
 package uts
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
 	"sync"
 
 	"github.com/YLonely/cer-manager/api/types"
-	"github.com/YLonely/cer-manager/log"
 	"github.com/YLonely/cer-manager/namespace"
+	"github.com/YLonely/criuimages"
+	criutype "github.com/YLonely/criuimages/types"
 	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
 )
 
-func NewManager(capacities []int, refs []types.Reference) (namespace.Manager, error) {
+func init() {
+	namespace.PutNamespaceFunction(namespace.NamespaceFunctionKeyCreate, types.NamespaceUTS, setNames)
+	namespace.PutNamespaceFunction(namespace.NamespaceFunctionKeyReset, types.NamespaceUTS, setNames)
+	namespace.PutNamespaceFunction(namespace.NamespaceFunctionKeyCheck, types.NamespaceUTS, checkNames)
+}
+
+func NewManager(capacities []int, refs []types.Reference, supplier types.Supplier, refiller *namespace.Refiller, budget *namespace.Budget, owners *namespace.Owners) (namespace.Manager, error) {
 	m := &manager{
-		sets: map[string]*namespace.Set{},
-		usedNamespace: map[int]struct {
-			ref types.Reference
-			f   *os.File
-		}{},
+		names:    map[string]utsName{},
+		supplier: supplier,
+		owners:   owners,
 	}
+	pools, err := namespace.NewPoolManager(namespace.PoolConfig{
+		Type:     types.NamespaceUTS,
+		Select:   m.pick,
+		Refiller: refiller,
+		Budget:   budget,
+	})
+	if err != nil {
+		return nil, err
+	}
+	m.PoolManager = pools
 	for i, ref := range refs {
-		if err := m.initSet(ref, capacities[i]); err != nil {
+		if err := m.add(ref, capacities[i]); err != nil {
 			return nil, err
 		}
 	}
@@ -27,111 +45,142 @@ func NewManager(capacities []int, refs []types.Reference) (namespace.Manager, er
 }
 
 type manager struct {
-	m             sync.Mutex
-	sets          map[string]*namespace.Set
-	usedNamespace map[int]struct {
-		ref types.Reference
-		f   *os.File
-	}
+	*namespace.PoolManager
+	m sync.Mutex
+	// names maps the digest of a reference to the names its namespaces are reset to on Put
+	names    map[string]utsName
+	supplier types.Supplier
+	owners   *namespace.Owners
 }
 
-func (m *manager) Get(ref types.Reference, extraRefs ...types.Reference) (fd int, info interface{}, err error) {
-	if len(extraRefs) != 0 {
-		err = errors.New("extra references is not supported")
-		return
-	}
+// pick chooses the names shared by the most of refs, the earlier ones win a tie, and takes an idle namespace
+// of the first reference with the names which has one. It returns the reference picked and why,
+// f is nil if none of them has an idle namespace and the first of them is picked to wait on
+func (m *manager) pick(refs []types.Reference) (target types.Reference, f *os.File, reason string, err error) {
 	m.m.Lock()
 	defer m.m.Unlock()
-	set, exists := m.sets[ref.Digest()]
-	if !exists {
-		err = errors.Errorf("UTS namespaces of ref %s does not exist", ref)
-		return
-	}
-	f := set.Get()
-	if f == nil {
-		err = errors.Errorf("UTS namespace of ref %s is used up")
-		return
-	}
-	// Keep the number of namespace resources at the set value(capacity)
-	go func() {
-		m.m.Lock()
-		defer m.m.Unlock()
-		if set.Capacity() >= set.DefaultCapacity() {
-			return
+	var (
+		names  []utsName
+		groups = map[utsName][]types.Reference{}
+		picked = map[string]bool{}
+	)
+	for _, ref := range refs {
+		name, exists := m.names[ref.Digest()]
+		if !exists || picked[ref.Digest()] {
+			continue
 		}
-		if err := set.CreateOne(); err != nil {
-			log.Raw().WithError(err).Errorf("failed to create a new UTS namespace for %s", ref)
+		picked[ref.Digest()] = true
+		if _, seen := groups[name]; !seen {
+			names = append(names, name)
 		}
-	}()
-	fd = int(f.Fd())
-	m.usedNamespace[fd] = struct {
-		ref types.Reference
-		f   *os.File
-	}{
-		ref: ref,
-		f:   f,
-	}
-	return
-}
-
-func (m *manager) Put(fd int) error {
-	m.m.Lock()
-	defer m.m.Unlock()
-	item, exists := m.usedNamespace[fd]
-	if !exists {
-		return errors.Errorf("invalid namespace fd %d", fd)
+		groups[name] = append(groups[name], ref)
 	}
-	set, exists := m.sets[item.ref.Digest()]
-	if !exists {
-		panic(errors.Errorf("namespace set of ref %s does not exist", item.ref))
+	if len(names) == 0 {
+		return refs[0], nil, "", nil
 	}
-	set.Add(item.f)
-	delete(m.usedNamespace, fd)
-	return nil
+	best := names[0]
+	for _, name := range names[1:] {
+		if len(groups[name]) > len(groups[best]) {
+			best = name
+		}
+	}
+	reason = fmt.Sprintf("%d of %d references have hostname %q and domainname %q", len(groups[best]), len(picked), best.hostname, best.domainname)
+	for _, ref := range groups[best] {
+		if f = m.SetOf(ref).Get(); f != nil {
+			return ref, f, reason, nil
+		}
+	}
+	return groups[best][0], nil, reason + ", none of them has an idle namespace", nil
 }
 
-func (m *manager) initSet(ref types.Reference, capacity int) error {
-	set, err := namespace.NewSet(capacity, newUTSNamespace, func(f *os.File) error { return nil })
+// add creates the pool of ref, the caller holds the lock unless the manager is being created
+func (m *manager) add(ref types.Reference, capacity int) error {
+	cp, err := m.supplier.Get(ref)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get checkpoint path for %s", ref)
+	}
+	name, err := checkpointedUTSName(cp)
 	if err != nil {
-		return errors.Errorf("failed to create namespace set for ref %s", ref)
+		return errors.Wrapf(err, "failed to get the UTS names of %s", ref)
 	}
-	m.sets[ref.Digest()] = set
+	owner := m.owners.Path(ref, types.NamespaceUTS)
+	err = m.Add(ref, namespace.Pool{
+		Capacity: capacity,
+		Cost:     types.Resources{Namespaces: 1},
+		Create:   func() (*os.File, error) { return name.newNamespace(owner) },
+		Check:    name.check,
+		// the borrower may have changed the names, reset them before the namespace is reused
+		Put:   namespace.PutRecycle,
+		Reset: name.reset,
+	})
+	if err != nil {
+		return err
+	}
+	m.names[ref.Digest()] = name
 	return nil
 }
 
+// Update resizes the pool of ref, the pool is created if ref is not pooled yet
 func (m *manager) Update(ref types.Reference, capacity int) error {
 	m.m.Lock()
 	defer m.m.Unlock()
-	set, exists := m.sets[ref.Digest()]
-	if !exists {
-		if err := m.initSet(ref, capacity); err != nil {
-			return err
-		}
-		return nil
+	if m.SetOf(ref) == nil {
+		return m.add(ref, capacity)
 	}
-	return set.Update(capacity)
+	return m.PoolManager.Update(ref, capacity)
 }
 
-func (m *manager) CleanUp() error {
-	var last error
-	for digest, set := range m.sets {
-		if err := set.CleanUp(); err != nil {
-			last = err
-			log.Raw().WithError(err).Errorf("failed to clean up the UTS namespace set of %s", digest)
-		}
+// utsName is the names of a UTS namespace
+type utsName struct {
+	hostname   string
+	domainname string
+}
+
+// checkpointedUTSName returns the names in the utsns image of the checkpoint,
+// or the names of the UTS namespace we are in if the image does not exist
+func checkpointedUTSName(checkpoint string) (utsName, error) {
+	images, err := filepath.Glob(filepath.Join(checkpoint, "utsns-*.img"))
+	if err != nil {
+		return utsName{}, err
+	}
+	if len(images) == 0 {
+		return currentUTSName()
+	}
+	img, err := criuimages.New(images[0])
+	if err != nil {
+		return utsName{}, errors.Wrap(err, "failed to open image "+images[0])
+	}
+	defer img.Close()
+	entry := &criutype.UtsnsEntry{}
+	if err = img.ReadOne(entry); err != nil {
+		return utsName{}, errors.Wrap(err, "failed to read image "+images[0])
 	}
-	for _, item := range m.usedNamespace {
-		log.Raw().Warnf("namespace file %d is being used", item.f.Fd())
-		item.f.Close()
+	return utsName{
+		hostname:   entry.GetNodename(),
+		domainname: entry.GetDomainname(),
+	}, nil
+}
+
+// currentUTSName returns the names of the UTS namespace we are in
+func currentUTSName() (utsName, error) {
+	var uts unix.Utsname
+	if err := unix.Uname(&uts); err != nil {
+		return utsName{}, errors.Wrap(err, "failed to get the current UTS names")
 	}
-	return last
+	return utsName{
+		hostname:   unix.ByteSliceToString(uts.Nodename[:]),
+		domainname: unix.ByteSliceToString(uts.Domainname[:]),
+	}, nil
 }
 
-func newUTSNamespace() (*os.File, error) {
-	h, err := namespace.NewNamespaceExecCreateHelper("", types.NamespaceUTS, nil)
+// newNamespace creates a UTS namespace with the names n in the user namespace at owner,
+// or in the one of the daemon if owner is empty
+func (n utsName) newNamespace(owner string) (*os.File, error) {
+	h, err := namespace.NewNamespaceExecCreateHelper(namespace.NamespaceFunctionKeyCreate, types.NamespaceUTS, n.args())
 	if err != nil {
 		return nil, err
 	}
+	h.JoinUserNamespace(owner)
 	if err := h.Do(false); err != nil {
 		return nil, errors.Wrap(err, "failed to create new UTS namespace")
 	}
@@ -142,3 +191,71 @@ func newUTSNamespace() (*os.File, error) {
 	}
 	return nsFile, nil
 }
+
+func (n utsName) args() map[string]string {
+	return map[string]string{
+		"hostname":   n.hostname,
+		"domainname": n.domainname,
+	}
+}
+
+// reset enters the namespace f and sets its names back to n
+func (n utsName) reset(f *os.File) error {
+	h, err := namespace.NewNamespaceExecEnterHelper(
+		namespace.NamespaceFunctionKeyReset,
+		types.NamespaceUTS,
+		fmt.Sprintf("/proc/%d/fd/%d", os.Getpid(), int(f.Fd())),
+		n.args(),
+	)
+	if err != nil {
+		return err
+	}
+	return h.Do(true)
+}
+
+// check enters the namespace f and checks that its names are still n
+func (n utsName) check(f *os.File) error {
+	h, err := namespace.NewNamespaceExecEnterHelper(
+		namespace.NamespaceFunctionKeyCheck,
+		types.NamespaceUTS,
+		fmt.Sprintf("/proc/%d/fd/%d", os.Getpid(), int(f.Fd())),
+		n.args(),
+	)
+	if err != nil {
+		return err
+	}
+	return h.Do(true)
+}
+
+// checkNames checks the names of the UTS namespace we are in against the ones in args
+func checkNames(args map[string]interface{}) ([]byte, error) {
+	hostname, _ := args["hostname"].(string)
+	domainname, _ := args["domainname"].(string)
+	current, err := currentUTSName()
+	if err != nil {
+		return nil, err
+	}
+	if current.hostname != hostname {
+		return nil, errors.Errorf("hostname is %q instead of %q", current.hostname, hostname)
+	}
+	if current.domainname != domainname {
+		return nil, errors.Errorf("domainname is %q instead of %q", current.domainname, domainname)
+	}
+	return nil, nil
+}
+
+// setNames sets the names of the UTS namespace we are in
+func setNames(args map[string]interface{}) ([]byte, error) {
+	hostname, _ := args["hostname"].(string)
+	domainname, _ := args["domainname"].(string)
+	if hostname == "" {
+		return nil, errors.New("hostname must be provided")
+	}
+	if err := unix.Sethostname([]byte(hostname)); err != nil {
+		return nil, errors.Wrapf(err, "failed to set hostname to %s", hostname)
+	}
+	if err := unix.Setdomainname([]byte(domainname)); err != nil {
+		return nil, errors.Wrapf(err, "failed to set domainname to %s", domainname)
+	}
+	return nil, nil
+}
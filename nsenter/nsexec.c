@@ -1,5 +1,6 @@
 #define _GNU_SOURCE
 #include <fcntl.h>
+#include <limits.h>
 #include <sched.h>
 #include <stdio.h>
 #include <stdlib.h>
@@ -8,9 +9,16 @@
 
 #define OP_TYPE_CREATE "CREATE"
 #define OP_TYPE_ENTER "ENTER"
+#define OP_TYPE_CLONE "CLONE"
 #define OP_TYPE_KEY "__OP_TYPE__"
 #define NS_TYPE_KEY "__NS_TYPE__"
 #define NS_PATH_KEY "__NS_PATH__"
+#define CGROUP_PATH_KEY "__CGROUP_PATH__"
+#define USERNS_PATH_KEY "__USERNS_PATH__"
+
+#ifndef CLONE_NEWTIME
+#define CLONE_NEWTIME 0x00000080
+#endif
 
 char msg_arr[1024];
 
@@ -27,6 +35,14 @@ int get_ns_flag(char *ns_type) {
         return CLONE_NEWUTS;
     else if (!strcmp(ns_type, "ipc"))
         return CLONE_NEWIPC;
+    else if (!strcmp(ns_type, "net"))
+        return CLONE_NEWNET;
+    else if (!strcmp(ns_type, "cgroup"))
+        return CLONE_NEWCGROUP;
+    else if (!strcmp(ns_type, "user"))
+        return CLONE_NEWUSER;
+    else if (!strcmp(ns_type, "time"))
+        return CLONE_NEWTIME;
     return -1;
 }
 
@@ -46,6 +62,47 @@ void nsenter(int flag) {
     close(fd);
 }
 
+// join_cgroup moves us to the cgroup in CGROUP_PATH_KEY if it is set,
+// a new cgroup namespace is rooted at the cgroup of its creator
+void join_cgroup() {
+    char *cgroup_path = getenv(CGROUP_PATH_KEY);
+    if (cgroup_path == NULL)
+        return;
+    char procs_path[PATH_MAX];
+    snprintf(procs_path, sizeof(procs_path), "%s/cgroup.procs", cgroup_path);
+    int fd;
+    if ((fd = open(procs_path, O_WRONLY)) == -1) {
+        snprintf(msg_arr, sizeof(msg_arr), "Can't open %.1000s", procs_path);
+        error(msg_arr);
+    }
+    if (write(fd, "0", 1) != 1) {
+        close(fd);
+        snprintf(msg_arr, sizeof(msg_arr), "Can't join cgroup %s", cgroup_path);
+        error(msg_arr);
+    }
+    close(fd);
+}
+
+// join_userns moves us to the user namespace in USERNS_PATH_KEY if it is set and makes us its root,
+// the namespaces we create then are owned by it and the files we create are owned by its root
+void join_userns() {
+    char *userns_path = getenv(USERNS_PATH_KEY);
+    if (userns_path == NULL)
+        return;
+    int fd;
+    if ((fd = open(userns_path, O_RDONLY)) == -1) {
+        snprintf(msg_arr, sizeof(msg_arr), "Can't open user ns file %.990s", userns_path);
+        error(msg_arr);
+    }
+    if (setns(fd, CLONE_NEWUSER)) {
+        close(fd);
+        error("setns to user ns failed");
+    }
+    close(fd);
+    if (setresgid(0, 0, 0) || setresuid(0, 0, 0))
+        error("Can't become the root of the user ns");
+}
+
 void nscreate(int flag) {
     if (unshare(flag))
         error("unshare failed");
@@ -63,10 +120,21 @@ void nsexec() {
         sprintf(msg_arr, "Invalid ns_type %s", ns_type);
         error(msg_arr);
     }
-    if (!strcmp(op_type, OP_TYPE_CREATE))
+    if (!strcmp(op_type, OP_TYPE_CREATE)) {
+        join_cgroup();
+        join_userns();
         nscreate(flag);
-    else if (!strcmp(op_type, OP_TYPE_ENTER))
+    }
+    else if (!strcmp(op_type, OP_TYPE_ENTER)) {
+        join_userns();
+        nsenter(flag);
+    }
+    else if (!strcmp(op_type, OP_TYPE_CLONE)) {
+        // enter the namespace and unshare a copy of it
+        join_userns();
         nsenter(flag);
+        nscreate(flag);
+    }
     else {
         sprintf(msg_arr, "Invalid op_type %s", op_type);
         error(msg_arr);
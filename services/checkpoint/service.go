@@ -10,8 +10,10 @@ import (
 	"strings"
 	"sync"
 
+	apiservices "github.com/YLonely/cer-manager/api/services"
 	api "github.com/YLonely/cer-manager/api/services/checkpoint"
 	"github.com/YLonely/cer-manager/api/types"
+	"github.com/YLonely/cer-manager/auth"
 	cp "github.com/YLonely/cer-manager/checkpoint"
 	"github.com/YLonely/cer-manager/checkpoint/ccfs"
 	"github.com/YLonely/cer-manager/checkpoint/containerd"
@@ -39,9 +41,10 @@ func New(root string) (services.Service, error) {
 		return nil, errors.Wrap(err, "failed to unmarshal config file")
 	}
 	s := &service{
-		root:    path.Join(root, "checkpoint"),
-		router:  services.NewRouter(),
-		targets: map[string]struct{}{},
+		root:     path.Join(root, "checkpoint"),
+		router:   services.NewRouter(),
+		targets:  map[string]struct{}{},
+		borrowed: map[uint64][]types.Reference{},
 	}
 	err = s.initProvider(c)
 	if err != nil {
@@ -59,6 +62,8 @@ type service struct {
 	targets      map[string]struct{}
 	m            sync.Mutex
 	doneProvider func() error
+	// borrowed records the references in use by each connection
+	borrowed map[uint64][]types.Reference
 }
 
 type config struct {
@@ -69,6 +74,8 @@ type config struct {
 }
 
 var _ services.Service = &service{}
+var _ services.Reclaimer = &service{}
+var _ types.SharedSupplier = &service{}
 
 func (s *service) Init() error {
 	if err := os.MkdirAll(s.root, 0755); err != nil {
@@ -81,12 +88,16 @@ func (s *service) Init() error {
 }
 
 func (s *service) Handle(ctx context.Context, c net.Conn) {
-	if err := s.router.Handle(c); err != nil {
+	if err := s.router.Handle(ctx, c); err != nil {
 		log.Logger(cerm.CheckpointService, "").Error(err)
 		c.Close()
 	}
 }
 
+func (s *service) Methods() []string {
+	return s.router.Methods()
+}
+
 func (s *service) Stop() error {
 	var failed []string
 	for t := range s.targets {
@@ -107,7 +118,7 @@ func (s *service) Stop() error {
 
 func (s *service) Get(ref types.Reference) (string, error) {
 	if ref.Name == "" {
-		return "", errors.New("empty ref")
+		return "", apiservices.Errorf(apiservices.CodeInvalidArgument, "empty ref")
 	}
 	target := path.Join(s.root, ref.Digest())
 	s.m.Lock()
@@ -125,21 +136,32 @@ func (s *service) Get(ref types.Reference) (string, error) {
 	return target, nil
 }
 
-func (s *service) handleGetCheckpoint(c net.Conn) error {
+// Acquire returns the checkpoint of ref and counts a user on it
+func (s *service) Acquire(ref types.Reference) (string, error) {
+	target, err := s.Get(ref)
+	if err != nil {
+		return "", err
+	}
+	if s.sharedMgr != nil {
+		s.sharedMgr.Add(ref)
+	}
+	return target, nil
+}
+
+// Release removes a user of the checkpoint of ref
+func (s *service) Release(ref types.Reference) {
+	if s.sharedMgr != nil {
+		s.sharedMgr.Release(ref)
+	}
+}
+
+func (s *service) handleGetCheckpoint(ctx context.Context, c net.Conn) error {
 	var r api.GetCheckpointRequest
 	if err := utils.ReceiveObject(c, &r); err != nil {
 		return err
 	}
 	log.WithInterface(log.Logger(cerm.CheckpointService, "GetCheckpoint"), "request", r).Debug()
-	var resp api.GetCheckpointResponse
-	var err error
-	resp.Path, err = s.Get(r.Ref)
-	if err != nil {
-		log.Logger(cerm.CheckpointService, "GetCheckpoint").Error(err)
-	}
-	if s.sharedMgr != nil {
-		s.sharedMgr.Add(r.Ref)
-	}
+	resp := s.getCheckpoint(ctx, r)
 	if err := utils.SendObject(c, resp); err != nil {
 		return err
 	}
@@ -147,16 +169,13 @@ func (s *service) handleGetCheckpoint(c net.Conn) error {
 	return nil
 }
 
-func (s *service) handlePutCheckpoint(c net.Conn) error {
+func (s *service) handlePutCheckpoint(ctx context.Context, c net.Conn) error {
 	var r api.PutCheckpointRequest
 	if err := utils.ReceiveObject(c, &r); err != nil {
 		return err
 	}
 	log.WithInterface(log.Logger(cerm.CheckpointService, "PutCheckpoint"), "request", r).Debug()
-	var resp api.PutCheckpointResponse
-	if s.sharedMgr != nil {
-		s.sharedMgr.Release(r.Ref)
-	}
+	resp := s.putCheckpoint(ctx, r)
 	if err := utils.SendObject(c, resp); err != nil {
 		return err
 	}
@@ -164,6 +183,90 @@ func (s *service) handlePutCheckpoint(c net.Conn) error {
 	return nil
 }
 
+// getCheckpoint and putCheckpoint do the real work for the handlers of all the transports
+
+func (s *service) getCheckpoint(ctx context.Context, r api.GetCheckpointRequest) api.GetCheckpointResponse {
+	var resp api.GetCheckpointResponse
+	err := auth.Authorize(ctx, auth.Request{
+		Service: cerm.CheckpointService,
+		Method:  api.MethodGetCheckpoint,
+		Refs:    []types.Reference{r.Ref},
+	})
+	if err != nil {
+		resp.Error = apiservices.ToError(err)
+		return resp
+	}
+	resp.Path, err = s.Acquire(r.Ref)
+	if err != nil {
+		log.Logger(cerm.CheckpointService, "GetCheckpoint").Error(err)
+		resp.Error = apiservices.ToError(err)
+	} else if s.sharedMgr != nil {
+		if conn, ok := services.ConnectionFromContext(ctx); ok && !r.Detach {
+			s.m.Lock()
+			s.borrowed[conn] = append(s.borrowed[conn], r.Ref)
+			s.m.Unlock()
+		}
+	}
+	return resp
+}
+
+func (s *service) putCheckpoint(ctx context.Context, r api.PutCheckpointRequest) api.PutCheckpointResponse {
+	var resp api.PutCheckpointResponse
+	if err := auth.Authorize(ctx, auth.Request{
+		Service: cerm.CheckpointService,
+		Method:  api.MethodPutCheckpoint,
+		Refs:    []types.Reference{r.Ref},
+	}); err != nil {
+		resp.Error = apiservices.ToError(err)
+		return resp
+	}
+	if s.sharedMgr != nil {
+		s.Release(r.Ref)
+		conn, _ := services.ConnectionFromContext(ctx)
+		s.m.Lock()
+		s.forget(conn, r.Ref)
+		s.m.Unlock()
+	}
+	return resp
+}
+
+// forget removes one record of ref, the one of conn is preferred as the checkpoint may be put back by others
+func (s *service) forget(conn uint64, ref types.Reference) {
+	conns := []uint64{conn}
+	for c := range s.borrowed {
+		if c != conn {
+			conns = append(conns, c)
+		}
+	}
+	digest := ref.Digest()
+	for _, c := range conns {
+		refs := s.borrowed[c]
+		for i := range refs {
+			if refs[i].Digest() == digest {
+				refs = append(refs[:i], refs[i+1:]...)
+				if len(refs) == 0 {
+					delete(s.borrowed, c)
+				} else {
+					s.borrowed[c] = refs
+				}
+				return
+			}
+		}
+	}
+}
+
+// Reclaim releases the checkpoints still used by the closed connection
+func (s *service) Reclaim(conn uint64) {
+	s.m.Lock()
+	refs := s.borrowed[conn]
+	delete(s.borrowed, conn)
+	s.m.Unlock()
+	for _, ref := range refs {
+		s.sharedMgr.Release(ref)
+		log.Logger(cerm.CheckpointService, "Reclaim").Infof("release checkpoint %s from closed connection %d", ref, conn)
+	}
+}
+
 func (s *service) initProvider(c config) error {
 	var err error
 	switch c.Type {
@@ -0,0 +1,48 @@
+package checkpoint
+
+import (
+	"context"
+
+	cerm "github.com/YLonely/cer-manager"
+	api "github.com/YLonely/cer-manager/api/services/checkpoint"
+	cpv1 "github.com/YLonely/cer-manager/api/services/checkpoint/v1"
+	typesv1 "github.com/YLonely/cer-manager/api/types/v1"
+	"github.com/YLonely/cer-manager/log"
+	"github.com/YLonely/cer-manager/services"
+	"github.com/containerd/ttrpc"
+)
+
+var _ services.TTRPCService = &service{}
+
+func (s *service) RegisterTTRPC(server *ttrpc.Server) error {
+	cpv1.RegisterCheckpointService(server, &ttrpcService{s: s})
+	return nil
+}
+
+// ttrpcService serves the checkpoint service over ttrpc
+type ttrpcService struct {
+	s *service
+}
+
+var _ cpv1.CheckpointService = &ttrpcService{}
+
+func (t *ttrpcService) Get(ctx context.Context, req *cpv1.GetCheckpointRequest) (*cpv1.GetCheckpointResponse, error) {
+	log.WithInterface(log.Logger(cerm.CheckpointService, "ttrpcGet"), "request", req).Debug()
+	resp := t.s.getCheckpoint(ctx, api.GetCheckpointRequest{
+		Ref: req.Ref.ToReference(),
+	})
+	return &cpv1.GetCheckpointResponse{
+		Path:  resp.Path,
+		Error: typesv1.FromError(resp.Error),
+	}, nil
+}
+
+func (t *ttrpcService) Put(ctx context.Context, req *cpv1.PutCheckpointRequest) (*cpv1.PutCheckpointResponse, error) {
+	log.WithInterface(log.Logger(cerm.CheckpointService, "ttrpcPut"), "request", req).Debug()
+	resp := t.s.putCheckpoint(ctx, api.PutCheckpointRequest{
+		Ref: req.Ref.ToReference(),
+	})
+	return &cpv1.PutCheckpointResponse{
+		Error: typesv1.FromError(resp.Error),
+	}, nil
+}
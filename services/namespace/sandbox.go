@@ -0,0 +1,234 @@
+package namespace
+
+import (
+	"context"
+	"net"
+	"os"
+	"time"
+
+	cerm "github.com/YLonely/cer-manager"
+	apiservices "github.com/YLonely/cer-manager/api/services"
+	cpapi "github.com/YLonely/cer-manager/api/services/checkpoint"
+	nsapi "github.com/YLonely/cer-manager/api/services/namespace"
+	"github.com/YLonely/cer-manager/api/types"
+	"github.com/YLonely/cer-manager/auth"
+	"github.com/YLonely/cer-manager/log"
+	"github.com/YLonely/cer-manager/services"
+	"github.com/YLonely/cer-manager/utils"
+	"github.com/pkg/errors"
+)
+
+// defaultSandboxTypes are the namespace types needed to restore a container
+var defaultSandboxTypes = []types.NamespaceType{
+	types.NamespaceIPC,
+	types.NamespaceUTS,
+	types.NamespaceMNT,
+}
+
+// sandbox records the namespaces and the checkpoint checked out together
+type sandbox struct {
+	ref        types.Reference
+	namespaces []nsapi.SandboxNamespace
+	// shared reports whether the checkpoint is acquired from a SharedSupplier
+	shared bool
+	// conn is the connection holding the sandbox if tracked is true
+	conn    uint64
+	tracked bool
+}
+
+// holds reports whether the namespace of key is checked out with sb
+func (sb *sandbox) holds(key borrowedNamespace) bool {
+	for _, ns := range sb.namespaces {
+		if ns.T == key.t && ns.ID == key.id {
+			return true
+		}
+	}
+	return false
+}
+
+func (svr *namespaceService) handleGetSandbox(ctx context.Context, conn net.Conn) error {
+	var r nsapi.GetSandboxRequest
+	if err := utils.ReceiveObject(conn, &r); err != nil {
+		return err
+	}
+	log.WithInterface(log.Logger(cerm.NamespaceService, "handleGetSandbox"), "request", r).Debug()
+	rsp, files := svr.getSandbox(ctx, r)
+	if err := utils.SendObjectWithFiles(conn, rsp, files...); err != nil {
+		return err
+	}
+	log.WithInterface(log.Logger(cerm.NamespaceService, "handleGetSandbox"), "response", rsp).Debug()
+	return nil
+}
+
+func (svr *namespaceService) handlePutSandbox(ctx context.Context, conn net.Conn) error {
+	var r nsapi.PutSandboxRequest
+	if err := utils.ReceiveObject(conn, &r); err != nil {
+		return err
+	}
+	log.WithInterface(log.Logger(cerm.NamespaceService, "handlePutSandbox"), "request", r).Debug()
+	rsp := svr.putSandbox(ctx, r)
+	if err := utils.SendObject(conn, rsp); err != nil {
+		return err
+	}
+	log.WithInterface(log.Logger(cerm.NamespaceService, "handlePutSandbox"), "response", rsp).Debug()
+	return nil
+}
+
+// getSandbox checks out all the namespaces and the checkpoint, or none of them
+func (svr *namespaceService) getSandbox(ctx context.Context, r nsapi.GetSandboxRequest) (nsapi.GetSandboxResponse, []*os.File) {
+	rsp := nsapi.GetSandboxResponse{}
+	nsTypes := r.Types
+	if len(nsTypes) == 0 {
+		nsTypes = defaultSandboxTypes
+	}
+	if err := svr.authorizeSandbox(ctx, nsapi.MethodGetSandbox, r.Ref, nsTypes); err != nil {
+		rsp.Error = apiservices.ToError(err)
+		return rsp, nil
+	}
+	if err := auth.Authorize(ctx, auth.Request{
+		Service: cerm.CheckpointService,
+		Method:  cpapi.MethodGetCheckpoint,
+		Refs:    []types.Reference{r.Ref},
+	}); err != nil {
+		rsp.Error = apiservices.ToError(err)
+		return rsp, nil
+	}
+	seen := map[types.NamespaceType]struct{}{}
+	for _, t := range nsTypes {
+		if _, exists := svr.managers[t]; !exists {
+			rsp.Error = errNoSuchNamespace(t)
+			return rsp, nil
+		}
+		if _, exists := seen[t]; exists {
+			rsp.Error = apiservices.Errorf(apiservices.CodeInvalidArgument, "duplicated namespace type %s", t)
+			return rsp, nil
+		}
+		seen[t] = struct{}{}
+	}
+	id, err := utils.RandomID()
+	if err != nil {
+		rsp.Error = apiservices.ToError(err)
+		return rsp, nil
+	}
+	sb := &sandbox{
+		ref: r.Ref,
+	}
+	if shared, ok := svr.supplier.(types.SharedSupplier); ok {
+		rsp.CheckpointPath, err = shared.Acquire(r.Ref)
+		sb.shared = true
+	} else {
+		rsp.CheckpointPath, err = svr.supplier.Get(r.Ref)
+	}
+	if err != nil {
+		rsp.Error = apiservices.ToError(errors.Wrapf(err, "failed to get the checkpoint of %s", r.Ref))
+		return rsp, nil
+	}
+	ttl := r.LeaseTTL
+	if ttl == 0 {
+		ttl = svr.leaseTTL
+	}
+	deadline := time.Now().Add(r.Wait)
+	var files []*os.File
+	for _, t := range nsTypes {
+		mgr := svr.managers[t]
+		wait := time.Until(deadline)
+		if wait < 0 {
+			wait = 0
+		}
+		handle, f, info, err := mgr.Get(r.Ref, wait)
+		if err != nil {
+			svr.releaseSandbox(sb)
+			rsp.Error = apiservices.ToError(err)
+			return nsapi.GetSandboxResponse{Error: rsp.Error}, nil
+		}
+		ns := nsapi.SandboxNamespace{
+			T:    t,
+			ID:   handle,
+			Info: info,
+		}
+		sb.namespaces = append(sb.namespaces, ns)
+		if ttl != 0 {
+			lease, err := mgr.Lease(handle, ttl)
+			if err != nil {
+				svr.releaseSandbox(sb)
+				rsp.Error = apiservices.ToError(err)
+				return nsapi.GetSandboxResponse{Error: rsp.Error}, nil
+			}
+			ns.Lease = &lease
+		}
+		rsp.Namespaces = append(rsp.Namespaces, ns)
+		files = append(files, f)
+	}
+	sb.conn, sb.tracked = services.ConnectionFromContext(ctx)
+	sb.tracked = sb.tracked && !r.Detach
+	// the namespaces are reclaimed with the sandbox, their holders are only checked by Put and Renew
+	h := holderOf(ctx, true)
+	svr.m.Lock()
+	for _, ns := range sb.namespaces {
+		svr.borrowed[borrowedNamespace{t: ns.T, id: ns.ID}] = h
+	}
+	svr.sandboxes[id] = sb
+	svr.m.Unlock()
+	rsp.ID = id
+	return rsp, files
+}
+
+func (svr *namespaceService) putSandbox(ctx context.Context, r nsapi.PutSandboxRequest) nsapi.PutSandboxResponse {
+	rsp := nsapi.PutSandboxResponse{}
+	svr.m.Lock()
+	sb, exists := svr.sandboxes[r.ID]
+	svr.m.Unlock()
+	if !exists {
+		rsp.Error = apiservices.Errorf(apiservices.CodeNotFound, "sandbox %s does not exist", r.ID)
+		return rsp
+	}
+	var nsTypes []types.NamespaceType
+	for _, ns := range sb.namespaces {
+		nsTypes = append(nsTypes, ns.T)
+	}
+	if err := svr.authorizeSandbox(ctx, nsapi.MethodPutSandbox, sb.ref, nsTypes); err != nil {
+		rsp.Error = apiservices.ToError(err)
+		return rsp
+	}
+	svr.m.Lock()
+	if _, exists = svr.sandboxes[r.ID]; exists {
+		delete(svr.sandboxes, r.ID)
+	}
+	svr.m.Unlock()
+	if !exists {
+		rsp.Error = apiservices.Errorf(apiservices.CodeNotFound, "sandbox %s does not exist", r.ID)
+		return rsp
+	}
+	if err := svr.releaseSandbox(sb); err != nil {
+		rsp.Error = apiservices.ToError(err)
+	}
+	return rsp
+}
+
+func (svr *namespaceService) authorizeSandbox(ctx context.Context, method string, ref types.Reference, nsTypes []types.NamespaceType) error {
+	return auth.Authorize(ctx, auth.Request{
+		Service:        cerm.NamespaceService,
+		Method:         method,
+		Refs:           []types.Reference{ref},
+		NamespaceTypes: nsTypes,
+	})
+}
+
+// releaseSandbox puts back all the namespaces and releases the checkpoint of sb,
+// the namespaces already put back by the reaper of leases are skipped. It is called
+// as soon as the lease on any of the namespaces expires
+func (svr *namespaceService) releaseSandbox(sb *sandbox) error {
+	var last error
+	for _, ns := range sb.namespaces {
+		if err := svr.managers[ns.T].Put(ns.ID); err != nil && !errors.Is(err, apiservices.ErrNotFound) {
+			last = err
+			log.Logger(cerm.NamespaceService, "releaseSandbox").WithField("namespace", ns.T).Error(err)
+			continue
+		}
+		svr.forget(borrowedNamespace{t: ns.T, id: ns.ID})
+	}
+	if sb.shared {
+		svr.supplier.(types.SharedSupplier).Release(sb.ref)
+	}
+	return last
+}
@@ -7,16 +7,25 @@ import (
 	"net"
 	"os"
 	"path"
+	"runtime"
+	"sync"
+	"time"
 
 	cerm "github.com/YLonely/cer-manager"
 	ns "github.com/YLonely/cer-manager/namespace"
+	"github.com/YLonely/cer-manager/namespace/cgroup"
 	"github.com/YLonely/cer-manager/namespace/ipc"
 	"github.com/YLonely/cer-manager/namespace/mnt"
+	netns "github.com/YLonely/cer-manager/namespace/net"
+	"github.com/YLonely/cer-manager/namespace/timens"
+	"github.com/YLonely/cer-manager/namespace/user"
 	"github.com/YLonely/cer-manager/namespace/uts"
 
+	apiservices "github.com/YLonely/cer-manager/api/services"
 	nsapi "github.com/YLonely/cer-manager/api/services/namespace"
 
 	"github.com/YLonely/cer-manager/api/types"
+	"github.com/YLonely/cer-manager/auth"
 	"github.com/YLonely/cer-manager/log"
 	"github.com/YLonely/cer-manager/rootfs/containerd"
 	"github.com/YLonely/cer-manager/services"
@@ -29,8 +38,47 @@ type serviceConfig struct {
 		Name      string `json:"name"`
 		Namespace string `json:"namespace,omitempty"`
 		Capacity  int    `json:"capacity,omitempty"`
+		// Autoscale enables the autoscaler for the checkpoint, it overrides the bounds of the global one
+		Autoscale *autoscaleConfig `json:"autoscale,omitempty"`
+		// Priority orders the refilling of the namespaces of the checkpoints, higher first
+		Priority int `json:"priority,omitempty"`
+		// MountPutMode decides whether the mount namespaces of the checkpoint put back are recycled or destroyed
+		MountPutMode mnt.PutMode `json:"mount_put_mode,omitempty"`
+		// Cgroup is the cgroup v2 subtree the cgroup namespaces of the checkpoint are rooted at,
+		// it is relative to the root of cgroup v2. Setting it enables the cgroup namespaces of the checkpoint
+		Cgroup string `json:"cgroup,omitempty"`
+		// UserNamespace maps the user namespaces of the checkpoint, they are not served if it is nil
+		UserNamespace *user.Config `json:"user_namespace,omitempty"`
+		// Namespaces are the optional types of namespaces pooled for the checkpoint besides IPC, UTS and MNT,
+		// which are any of NET, CGROUP and TIME. None of them is pooled by default
+		Namespaces []types.NamespaceType `json:"namespaces,omitempty"`
 	} `json:"containerd_checkpoints"`
 	DefaultCapacity int `json:"default_capacity"`
+	// DefaultLeaseTTL is the ttl in seconds of the lease on each namespace checked out, leases are not used if it is zero
+	DefaultLeaseTTL int `json:"default_lease_ttl,omitempty"`
+	// Autoscale enables the autoscaler of the namespace sets of all the checkpoints,
+	// the checkpoints with their own bounds are autoscaled even if it is nil
+	Autoscale *autoscaleConfig `json:"autoscale,omitempty"`
+	// Refill configures the workers refilling the namespace sets
+	Refill refillConfig `json:"refill,omitempty"`
+	// Budget limits the resources held by the namespaces of all the checkpoints, a zero limit means unlimited
+	Budget types.Resources `json:"budget,omitempty"`
+	// MergeIPC merges the IPC objects of the checkpoints got together instead of rejecting them as a collision,
+	// as long as their keys, ids and vars do not conflict
+	MergeIPC bool `json:"merge_ipc,omitempty"`
+}
+
+// refillConfig configures the refiller, a set is refilled up to its capacity once the namespaces
+// in it fall below LowWatermark times its capacity
+type refillConfig struct {
+	Workers      int     `json:"workers,omitempty"`
+	LowWatermark float64 `json:"low_watermark,omitempty"`
+}
+
+// autoscaleConfig bounds the capacity of the namespace sets of a checkpoint
+type autoscaleConfig struct {
+	MinCapacity int `json:"min_capacity"`
+	MaxCapacity int `json:"max_capacity"`
 }
 
 func New(root string, supplier types.Supplier) (services.Service, error) {
@@ -51,9 +99,34 @@ func New(root string, supplier types.Supplier) (services.Service, error) {
 	if config.DefaultCapacity <= 0 {
 		return nil, errors.New("non-positive default capacity is invalid")
 	}
+	if config.DefaultLeaseTTL < 0 {
+		return nil, errors.New("negative default lease ttl is invalid")
+	}
+	if config.Budget.Namespaces < 0 || config.Budget.UpperBytes < 0 || config.Budget.ShmBytes < 0 {
+		return nil, errors.New("negative budget is invalid")
+	}
+	if config.Refill.Workers < 0 {
+		return nil, errors.New("negative number of refill workers is invalid")
+	} else if config.Refill.Workers == 0 {
+		config.Refill.Workers = runtime.NumCPU()
+	}
+	if config.Refill.LowWatermark < 0 || config.Refill.LowWatermark > 1 {
+		return nil, errors.Errorf("low watermark %v is not in [0, 1]", config.Refill.LowWatermark)
+	} else if config.Refill.LowWatermark == 0 {
+		config.Refill.LowWatermark = 1
+	}
 	log.WithInterface(log.Logger(cerm.NamespaceService, "New"), "config", config).Debug("create service with config")
 	refs := make([]types.Reference, 0, len(config.ContainerdCheckpoints))
 	capacities := make([]int, 0, len(config.ContainerdCheckpoints))
+	modes := make([]mnt.PutMode, 0, len(config.ContainerdCheckpoints))
+	cgroups := make([]string, 0, len(config.ContainerdCheckpoints))
+	users := make([]*user.Config, 0, len(config.ContainerdCheckpoints))
+	optional := map[types.NamespaceType][]bool{}
+	for _, t := range optionalTypes {
+		optional[t] = make([]bool, 0, len(config.ContainerdCheckpoints))
+	}
+	bounds := map[string]autoscaleConfig{}
+	refiller := ns.NewRefiller(config.Refill.Workers, config.Refill.LowWatermark)
 	for _, cp := range config.ContainerdCheckpoints {
 		ref := types.NewContainerdReference(cp.Name, cp.Namespace)
 		refs = append(refs, ref)
@@ -61,14 +134,68 @@ func New(root string, supplier types.Supplier) (services.Service, error) {
 			cp.Capacity = config.DefaultCapacity
 		}
 		capacities = append(capacities, cp.Capacity)
+		if cp.MountPutMode == "" {
+			cp.MountPutMode = mnt.PutModeDestroy
+		} else if !cp.MountPutMode.Valid() {
+			refiller.Stop()
+			return nil, errors.Errorf("invalid mount put mode %q of %s", cp.MountPutMode, ref)
+		}
+		modes = append(modes, cp.MountPutMode)
+		cgroups = append(cgroups, cp.Cgroup)
+		if cp.UserNamespace != nil {
+			if err := cp.UserNamespace.Validate(); err != nil {
+				refiller.Stop()
+				return nil, errors.Wrapf(err, "invalid user namespace config of %s", ref)
+			}
+		}
+		users = append(users, cp.UserNamespace)
+		// a checkpoint choosing the subtree of its cgroup namespaces pools them
+		enabled := map[types.NamespaceType]bool{types.NamespaceCGROUP: cp.Cgroup != ""}
+		for _, t := range cp.Namespaces {
+			if _, exists := optional[t]; !exists {
+				refiller.Stop()
+				return nil, errors.Errorf("%s namespaces of %s can not be enabled, only %v are optional", t, ref, optionalTypes)
+			}
+			enabled[t] = true
+		}
+		for _, t := range optionalTypes {
+			optional[t] = append(optional[t], enabled[t])
+		}
+		refiller.SetPriority(ref, cp.Priority)
+		var b autoscaleConfig
+		if cp.Autoscale != nil {
+			b = *cp.Autoscale
+		} else if config.Autoscale != nil {
+			b = *config.Autoscale
+		} else {
+			continue
+		}
+		if b.MinCapacity < 0 || b.MaxCapacity <= 0 || b.MinCapacity > b.MaxCapacity {
+			refiller.Stop()
+			return nil, errors.Errorf("invalid autoscale bounds [%d, %d] of %s", b.MinCapacity, b.MaxCapacity, ref)
+		}
+		bounds[ref.Digest()] = b
 	}
 	return &namespaceService{
 		capacities: capacities,
 		refs:       refs,
+		modes:      modes,
+		cgroups:    cgroups,
+		users:      users,
+		optional:   optional,
+		owners:     ns.NewOwners(),
 		managers:   map[types.NamespaceType]ns.Manager{},
 		root:       root,
 		router:     services.NewRouter(),
 		supplier:   supplier,
+		borrowed:   map[borrowedNamespace]holder{},
+		paths:      map[borrowedNamespace]*os.File{},
+		sandboxes:  map[string]*sandbox{},
+		leaseTTL:   time.Duration(config.DefaultLeaseTTL) * time.Second,
+		bounds:     bounds,
+		refiller:   refiller,
+		budget:     ns.NewBudget(config.Budget),
+		mergeIPC:   config.MergeIPC,
 	}, nil
 }
 
@@ -79,15 +206,119 @@ type namespaceService struct {
 	root       string
 	router     services.Router
 	supplier   types.Supplier
+	// borrowed maps the namespaces checked out to their holders
+	borrowed map[borrowedNamespace]holder
+	// paths maps the namespaces checked out over ttrpc to the files their paths point to
+	paths map[borrowedNamespace]*os.File
+	// sandboxes maps the sandbox id to the sandbox checked out
+	sandboxes map[string]*sandbox
+	m         sync.Mutex
+	// leaseTTL is the default ttl of the leases
+	leaseTTL time.Duration
+	// bounds maps the digest of a reference to its autoscale bounds, the references without bounds are not autoscaled
+	bounds   map[string]autoscaleConfig
+	refiller *ns.Refiller
+	budget   *ns.Budget
+	// modes are the put modes of the mount namespaces of refs
+	modes []mnt.PutMode
+	// cgroups are the subtrees the cgroup namespaces of refs are rooted at
+	cgroups []string
+	// users are the configs of the user namespaces of refs
+	users []*user.Config
+	// optional maps each of optionalTypes to whether the namespaces of the type are pooled for refs
+	optional map[types.NamespaceType][]bool
+	// owners are the user namespaces the namespaces of refs are created in
+	owners *ns.Owners
+	// mergeIPC enables the merged IPC namespaces of the references got together
+	mergeIPC bool
+}
+
+// optionalTypes are the types of namespaces pooled only for the checkpoints enabling them
+var optionalTypes = []types.NamespaceType{
+	types.NamespaceNET,
+	types.NamespaceCGROUP,
+	types.NamespaceTIME,
+}
+
+// pooled returns the capacities, the references and the cgroup subtrees of the checkpoints pooling the namespaces of type t
+func (svr *namespaceService) pooled(t types.NamespaceType) (capacities []int, refs []types.Reference, cgroups []string) {
+	for i, enabled := range svr.optional[t] {
+		if enabled {
+			capacities = append(capacities, svr.capacities[i])
+			refs = append(refs, svr.refs[i])
+			cgroups = append(cgroups, svr.cgroups[i])
+		}
+	}
+	return
+}
+
+type borrowedNamespace struct {
+	t  types.NamespaceType
+	id string
+}
+
+// holder records who checked out a namespace
+type holder struct {
+	// conn is the connection holding the namespace if tracked is true
+	conn    uint64
+	tracked bool
+	// peer is the peer the namespace is granted to, nil if the transport authorized the request
+	peer *auth.Credential
+}
+
+func holderOf(ctx context.Context, detach bool) holder {
+	h := holder{}
+	h.conn, h.tracked = services.ConnectionFromContext(ctx)
+	h.tracked = h.tracked && !detach
+	if cred, ok := auth.PeerFromContext(ctx); ok {
+		h.peer = &cred
+	}
+	return h
+}
+
+// authorizeHolder checks that the peer carried by ctx holds the namespace of key,
+// either on the connection it is checked out on or as the user it is granted to
+func (svr *namespaceService) authorizeHolder(ctx context.Context, key borrowedNamespace) error {
+	if auth.Unrestricted(ctx) {
+		return nil
+	}
+	svr.m.Lock()
+	h, exists := svr.borrowed[key]
+	svr.m.Unlock()
+	if exists {
+		if conn, ok := services.ConnectionFromContext(ctx); ok && h.tracked && h.conn == conn {
+			return nil
+		}
+		if cred, ok := auth.PeerFromContext(ctx); ok && h.peer != nil && h.peer.UID == cred.UID {
+			return nil
+		}
+	}
+	return apiservices.Errorf(apiservices.CodePermissionDenied, "%s namespace %s is not held by the peer", key.t, key.id)
 }
 
 var _ services.Service = &namespaceService{}
+var _ services.Reclaimer = &namespaceService{}
 
 func (svr *namespaceService) Init() error {
 	var err error
+	// the user namespaces go first as they own the namespaces of the other types
+	if svr.managers[types.NamespaceUSER], err = user.NewManager(
+		svr.capacities,
+		svr.refs,
+		svr.users,
+		svr.refiller,
+		svr.budget,
+		svr.owners,
+	); err != nil {
+		return errors.Wrap(err, "failed to create user namespace manager")
+	}
 	if svr.managers[types.NamespaceUTS], err = uts.NewManager(
 		svr.capacities,
 		svr.refs,
+		svr.supplier,
+		svr.refiller,
+		svr.budget,
+		svr.owners,
 	); err != nil {
 		return errors.Wrap(err, "failed to create uts namespace manager")
 	}
@@ -96,6 +327,10 @@ func (svr *namespaceService) Init() error {
 		svr.capacities,
 		svr.refs,
 		svr.supplier,
+		svr.refiller,
+		svr.budget,
+		svr.owners,
+		svr.mergeIPC,
 	); err != nil {
 		return errors.Wrap(err, "failed to create ipc namespace manager")
 	}
@@ -107,26 +342,173 @@ func (svr *namespaceService) Init() error {
 		svr.root,
 		svr.capacities,
 		svr.refs,
+		svr.modes,
 		p,
 		svr.supplier,
+		svr.refiller,
+		svr.budget,
+		svr.owners,
 	); err != nil {
 		return errors.Wrap(err, "failed to create mount namespace namager")
 	}
+	capacities, refs, cgroups := svr.pooled(types.NamespaceNET)
+	if svr.managers[types.NamespaceNET], err = netns.NewManager(
+		capacities,
+		refs,
+		svr.supplier,
+		svr.refiller,
+		svr.budget,
+	); err != nil {
+		return errors.Wrap(err, "failed to create net namespace manager")
+	}
+	capacities, refs, cgroups = svr.pooled(types.NamespaceCGROUP)
+	cgroupMgr, err := cgroup.NewManager(
+		capacities,
+		refs,
+		cgroups,
+		svr.refiller,
+		svr.budget,
+	)
+	if errors.Cause(err) == cgroup.ErrNotSupported {
+		log.Logger(cerm.NamespaceService, "Init").Warn("cgroup namespaces are not served as cgroup v2 is not mounted")
+	} else if err != nil {
+		return errors.Wrap(err, "failed to create cgroup namespace manager")
+	} else {
+		svr.managers[types.NamespaceCGROUP] = cgroupMgr
+	}
+	capacities, refs, _ = svr.pooled(types.NamespaceTIME)
+	timeMgr, err := timens.NewManager(
+		capacities,
+		refs,
+		svr.supplier,
+		svr.refiller,
+		svr.budget,
+	)
+	if errors.Cause(err) == timens.ErrNotSupported {
+		log.Logger(cerm.NamespaceService, "Init").Warn("time namespaces are not served as the kernel does not support them")
+	} else if err != nil {
+		return errors.Wrap(err, "failed to create time namespace manager")
+	} else {
+		svr.managers[types.NamespaceTIME] = timeMgr
+	}
+	for _, ref := range svr.refs {
+		b, exists := svr.bounds[ref.Digest()]
+		if !exists {
+			continue
+		}
+		for t, mgr := range svr.managers {
+			if err := mgr.Autoscale(ref, b.MinCapacity, b.MaxCapacity); err != nil {
+				return errors.Wrapf(err, "failed to autoscale %s namespaces of %s", t, ref)
+			}
+		}
+	}
+	for _, mgr := range svr.managers {
+		mgr.Notify(svr.expired)
+	}
 	svr.router.AddHandler(nsapi.MethodGetNamespace, svr.handleGetNamespace)
 	svr.router.AddHandler(nsapi.MethodPutNamespace, svr.handlePutNamespace)
 	svr.router.AddHandler(nsapi.MethodUpdateNamespace, svr.handleUpdateNamespace)
+	svr.router.AddHandler(nsapi.MethodRenewNamespace, svr.handleRenewNamespace)
+	svr.router.AddHandler(nsapi.MethodInspectNamespace, svr.handleInspectNamespace)
+	svr.router.AddHandler(nsapi.MethodGetSandbox, svr.handleGetSandbox)
+	svr.router.AddHandler(nsapi.MethodPutSandbox, svr.handlePutSandbox)
 	log.Logger(cerm.NamespaceService, "Init").Info("Service initialized")
 	return nil
 }
 
 func (svr *namespaceService) Handle(ctx context.Context, conn net.Conn) {
-	if err := svr.router.Handle(conn); err != nil {
+	if err := svr.router.Handle(ctx, conn); err != nil {
 		log.Logger(cerm.NamespaceService, "Handle").Error(err)
 		conn.Close()
 	}
 }
 
+func (svr *namespaceService) Methods() []string {
+	return svr.router.Methods()
+}
+
+// Reclaim puts back the namespaces and the sandboxes still held by the closed connection
+func (svr *namespaceService) Reclaim(conn uint64) {
+	svr.m.Lock()
+	var reclaimed []borrowedNamespace
+	for ns, h := range svr.borrowed {
+		if h.tracked && h.conn == conn {
+			reclaimed = append(reclaimed, ns)
+			delete(svr.borrowed, ns)
+		}
+	}
+	var sandboxes []*sandbox
+	for id, sb := range svr.sandboxes {
+		if sb.tracked && sb.conn == conn {
+			sandboxes = append(sandboxes, sb)
+			delete(svr.sandboxes, id)
+		}
+	}
+	svr.m.Unlock()
+	for _, sb := range sandboxes {
+		if err := svr.releaseSandbox(sb); err != nil {
+			log.Logger(cerm.NamespaceService, "Reclaim").Error(err)
+			continue
+		}
+		log.Logger(cerm.NamespaceService, "Reclaim").Infof("reclaim sandbox of %s from closed connection %d", sb.ref, conn)
+	}
+	for _, ns := range reclaimed {
+		if err := svr.managers[ns.t].Put(ns.id); err != nil {
+			log.Logger(cerm.NamespaceService, "Reclaim").WithField("namespace", ns.t).Error(err)
+			continue
+		}
+		log.Logger(cerm.NamespaceService, "Reclaim").Infof("reclaim %s namespace %s from closed connection %d", ns.t, ns.id, conn)
+	}
+}
+
+// expired drops the records of the namespace put back by the reaper of leases,
+// the sandbox the namespace belongs to is released as a whole
+func (svr *namespaceService) expired(l types.Lease) {
+	key := borrowedNamespace{t: l.NamespaceType, id: l.NamespaceID}
+	svr.forget(key)
+	svr.m.Lock()
+	var (
+		sb *sandbox
+		id string
+	)
+	for sid, s := range svr.sandboxes {
+		if s.holds(key) {
+			sb, id = s, sid
+			delete(svr.sandboxes, sid)
+			break
+		}
+	}
+	svr.m.Unlock()
+	if sb == nil {
+		return
+	}
+	if err := svr.releaseSandbox(sb); err != nil {
+		log.Logger(cerm.NamespaceService, "expired").Error(err)
+		return
+	}
+	log.Logger(cerm.NamespaceService, "expired").Infof("release sandbox %s of %s as lease %s expired", id, sb.ref, l.ID)
+}
+
+// forget drops the records of the namespace put back, its path is closed if it is checked out over ttrpc
+func (svr *namespaceService) forget(key borrowedNamespace) {
+	svr.m.Lock()
+	delete(svr.borrowed, key)
+	f := svr.paths[key]
+	delete(svr.paths, key)
+	svr.m.Unlock()
+	if f != nil {
+		f.Close()
+	}
+}
+
 func (svr *namespaceService) Stop() error {
+	svr.refiller.Stop()
+	svr.m.Lock()
+	for key, f := range svr.paths {
+		f.Close()
+		delete(svr.paths, key)
+	}
+	svr.m.Unlock()
 	for t, mgr := range svr.managers {
 		err := mgr.CleanUp()
 		if err != nil {
@@ -136,49 +518,31 @@ func (svr *namespaceService) Stop() error {
 	return nil
 }
 
-func (svr *namespaceService) handleGetNamespace(conn net.Conn) error {
+func (svr *namespaceService) handleGetNamespace(ctx context.Context, conn net.Conn) error {
 	var r nsapi.GetNamespaceRequest
 	if err := utils.ReceiveObject(conn, &r); err != nil {
 		return err
 	}
 	log.WithInterface(log.Logger(cerm.NamespaceService, "handleGetNamespace"), "request", r).Debug()
-	rsp := nsapi.GetNamespaceResponse{}
-	if mgr, exists := svr.managers[r.T]; !exists {
-		rsp.Fd = -1
-		rsp.Info = "No such namespace"
-	} else {
-		fd, info, err := mgr.Get(r.Ref)
-		if err != nil {
-			rsp.Fd = -1
-			rsp.Info = err.Error()
-		} else {
-			rsp.Fd = fd
-			rsp.Info = info
-			rsp.Pid = os.Getpid()
-		}
+	rsp, f := svr.getNamespace(ctx, r)
+	var files []*os.File
+	if f != nil {
+		files = append(files, f)
 	}
-	if err := utils.SendObject(conn, rsp); err != nil {
+	if err := utils.SendObjectWithFiles(conn, rsp, files...); err != nil {
 		return err
 	}
 	log.WithInterface(log.Logger(cerm.NamespaceService, "handleGetNamespace"), "response", rsp).Debug()
 	return nil
 }
 
-func (svr *namespaceService) handlePutNamespace(conn net.Conn) error {
+func (svr *namespaceService) handlePutNamespace(ctx context.Context, conn net.Conn) error {
 	var r nsapi.PutNamespaceRequest
 	if err := utils.ReceiveObject(conn, &r); err != nil {
 		return err
 	}
 	log.WithInterface(log.Logger(cerm.NamespaceService, "handlePutNamespace"), "request", r).Debug()
-	rsp := nsapi.PutNamespaceResponse{}
-	if mgr, exists := svr.managers[r.T]; !exists {
-		rsp.Error = "No such namespace"
-	} else {
-		err := mgr.Put(r.ID)
-		if err != nil {
-			rsp.Error = err.Error()
-		}
-	}
+	rsp := svr.putNamespace(ctx, r)
 	if err := utils.SendObject(conn, rsp); err != nil {
 		return err
 	}
@@ -186,22 +550,259 @@ func (svr *namespaceService) handlePutNamespace(conn net.Conn) error {
 	return nil
 }
 
-func (svr *namespaceService) handleUpdateNamespace(conn net.Conn) error {
+func (svr *namespaceService) handleUpdateNamespace(ctx context.Context, conn net.Conn) error {
 	var r nsapi.UpdateNamespaceRequest
 	if err := utils.ReceiveObject(conn, &r); err != nil {
 		return err
 	}
 	log.WithInterface(log.Logger(cerm.NamespaceService, "handleUpdateNamespace"), "request", r).Debug()
+	rsp := svr.updateNamespace(ctx, r)
+	if err := utils.SendObject(conn, rsp); err != nil {
+		return err
+	}
+	log.WithInterface(log.Logger(cerm.NamespaceService, "handleUpdateNamespace"), "response", rsp).Debug()
+	return nil
+}
+
+func (svr *namespaceService) handleRenewNamespace(ctx context.Context, conn net.Conn) error {
+	var r nsapi.RenewNamespaceRequest
+	if err := utils.ReceiveObject(conn, &r); err != nil {
+		return err
+	}
+	log.WithInterface(log.Logger(cerm.NamespaceService, "handleRenewNamespace"), "request", r).Debug()
+	rsp := svr.renewNamespace(ctx, r)
+	if err := utils.SendObject(conn, rsp); err != nil {
+		return err
+	}
+	log.WithInterface(log.Logger(cerm.NamespaceService, "handleRenewNamespace"), "response", rsp).Debug()
+	return nil
+}
+
+func (svr *namespaceService) handleInspectNamespace(ctx context.Context, conn net.Conn) error {
+	var r nsapi.InspectNamespaceRequest
+	if err := utils.ReceiveObject(conn, &r); err != nil {
+		return err
+	}
+	log.WithInterface(log.Logger(cerm.NamespaceService, "handleInspectNamespace"), "request", r).Debug()
+	rsp := svr.inspectNamespace(ctx, r)
+	if err := utils.SendObject(conn, rsp); err != nil {
+		return err
+	}
+	log.WithInterface(log.Logger(cerm.NamespaceService, "handleInspectNamespace"), "response", rsp).Debug()
+	return nil
+}
+
+// getNamespace, putNamespace, updateNamespace, renewNamespace and inspectNamespace
+// do the real work for the handlers of all the transports
+
+func (svr *namespaceService) getNamespace(ctx context.Context, r nsapi.GetNamespaceRequest) (nsapi.GetNamespaceResponse, *os.File) {
+	rsp := nsapi.GetNamespaceResponse{}
+	if err := auth.Authorize(ctx, auth.Request{
+		Service:        cerm.NamespaceService,
+		Method:         nsapi.MethodGetNamespace,
+		Refs:           append([]types.Reference{r.Ref}, r.ExtraRefs...),
+		NamespaceTypes: []types.NamespaceType{r.T},
+	}); err != nil {
+		rsp.Error = apiservices.ToError(err)
+		return rsp, nil
+	}
+	mgr, exists := svr.managers[r.T]
+	if !exists {
+		rsp.Error = errNoSuchNamespace(r.T)
+		return rsp, nil
+	}
+	handle, f, info, err := mgr.Get(r.Ref, r.Wait, r.ExtraRefs...)
+	if err != nil {
+		rsp.Error = apiservices.ToError(err)
+		return rsp, nil
+	}
+	ttl := r.LeaseTTL
+	if ttl == 0 {
+		ttl = svr.leaseTTL
+	}
+	if ttl != 0 {
+		lease, err := mgr.Lease(handle, ttl)
+		if err != nil {
+			mgr.Put(handle)
+			rsp.Error = apiservices.ToError(err)
+			return rsp, nil
+		}
+		rsp.Lease = &lease
+	}
+	svr.m.Lock()
+	svr.borrowed[borrowedNamespace{t: r.T, id: handle}] = holderOf(ctx, r.Detach)
+	svr.m.Unlock()
+	rsp.ID = handle
+	rsp.Info = info
+	return rsp, f
+}
+
+func (svr *namespaceService) putNamespace(ctx context.Context, r nsapi.PutNamespaceRequest) nsapi.PutNamespaceResponse {
+	rsp := nsapi.PutNamespaceResponse{}
+	mgr, exists := svr.managers[r.T]
+	if !exists {
+		rsp.Error = errNoSuchNamespace(r.T)
+		return rsp
+	}
+	refs, err := mgr.Refs(r.ID)
+	if err != nil {
+		rsp.Error = apiservices.ToError(err)
+		return rsp
+	}
+	key := borrowedNamespace{t: r.T, id: r.ID}
+	if err = svr.authorizeHandle(ctx, nsapi.MethodPutNamespace, key, refs); err != nil {
+		rsp.Error = apiservices.ToError(err)
+		return rsp
+	}
+	if err = mgr.Put(r.ID); err != nil {
+		rsp.Error = apiservices.ToError(err)
+		return rsp
+	}
+	svr.forget(key)
+	return rsp
+}
+
+// authorizeHandle checks that the peer carried by ctx may call method on the namespace of key got for refs and holds it
+func (svr *namespaceService) authorizeHandle(ctx context.Context, method string, key borrowedNamespace, refs []types.Reference) error {
+	if err := auth.Authorize(ctx, auth.Request{
+		Service:        cerm.NamespaceService,
+		Method:         method,
+		Refs:           refs,
+		NamespaceTypes: []types.NamespaceType{key.t},
+	}); err != nil {
+		return err
+	}
+	return svr.authorizeHolder(ctx, key)
+}
+
+func (svr *namespaceService) updateNamespace(ctx context.Context, r nsapi.UpdateNamespaceRequest) nsapi.UpdateNamespaceResponse {
 	rsp := nsapi.UpdateNamespaceResponse{}
+	if err := auth.Authorize(ctx, auth.Request{
+		Service: cerm.NamespaceService,
+		Method:  nsapi.MethodUpdateNamespace,
+		Refs:    []types.Reference{r.Ref},
+	}); err != nil {
+		rsp.Error = apiservices.ToError(err)
+		return rsp
+	}
+	if r.Capacity < 0 {
+		rsp.Error = apiservices.Errorf(apiservices.CodeInvalidArgument, "negative capacity %d is invalid", r.Capacity)
+		return rsp
+	}
 	for _, mgr := range svr.managers {
 		if err := mgr.Update(r.Ref, r.Capacity); err != nil {
-			rsp.Error = err.Error()
+			rsp.Error = apiservices.ToError(err)
 			break
 		}
 	}
-	if err := utils.SendObject(conn, rsp); err != nil {
-		return err
+	return rsp
+}
+
+func (svr *namespaceService) renewNamespace(ctx context.Context, r nsapi.RenewNamespaceRequest) nsapi.RenewNamespaceResponse {
+	rsp := nsapi.RenewNamespaceResponse{}
+	mgr, exists := svr.managers[r.T]
+	if !exists {
+		rsp.Error = errNoSuchNamespace(r.T)
+		return rsp
 	}
-	log.WithInterface(log.Logger(cerm.NamespaceService, "handleUpdateNamespace"), "response", rsp).Debug()
-	return nil
+	lease, err := mgr.Lookup(r.LeaseID)
+	if err != nil {
+		rsp.Error = apiservices.ToError(err)
+		return rsp
+	}
+	refs, err := mgr.Refs(lease.NamespaceID)
+	if err != nil {
+		rsp.Error = apiservices.ToError(err)
+		return rsp
+	}
+	if err = svr.authorizeHandle(ctx, nsapi.MethodRenewNamespace, borrowedNamespace{t: r.T, id: lease.NamespaceID}, refs); err != nil {
+		rsp.Error = apiservices.ToError(err)
+		return rsp
+	}
+	lease, err = mgr.Renew(r.LeaseID, r.TTL)
+	if err != nil {
+		rsp.Error = apiservices.ToError(err)
+		return rsp
+	}
+	rsp.Lease = lease
+	return rsp
+}
+
+func (svr *namespaceService) inspectNamespace(ctx context.Context, r nsapi.InspectNamespaceRequest) nsapi.InspectNamespaceResponse {
+	rsp := nsapi.InspectNamespaceResponse{
+		Leases: []types.Lease{},
+	}
+	req := auth.Request{
+		Service: cerm.NamespaceService,
+		Method:  nsapi.MethodInspectNamespace,
+	}
+	if r.T != "" {
+		req.NamespaceTypes = []types.NamespaceType{r.T}
+	} else {
+		for t := range svr.managers {
+			req.NamespaceTypes = append(req.NamespaceTypes, t)
+		}
+	}
+	if err := auth.Authorize(ctx, req); err != nil {
+		rsp.Error = apiservices.ToError(err)
+		return rsp
+	}
+	refill, budget := svr.refiller.Status(), svr.budget.Status()
+	rsp.Refill, rsp.Budget = &refill, &budget
+	if r.T != "" {
+		mgr, exists := svr.managers[r.T]
+		if !exists {
+			return nsapi.InspectNamespaceResponse{Error: errNoSuchNamespace(r.T)}
+		}
+		rsp.Leases = append(rsp.Leases, svr.visibleLeases(ctx, r.T, mgr)...)
+		rsp.Health = visibleHealth(ctx, mgr)
+		return rsp
+	}
+	for t, mgr := range svr.managers {
+		rsp.Leases = append(rsp.Leases, svr.visibleLeases(ctx, t, mgr)...)
+		rsp.Health = append(rsp.Health, visibleHealth(ctx, mgr)...)
+	}
+	return rsp
+}
+
+func errNoSuchNamespace(t types.NamespaceType) *apiservices.Error {
+	return apiservices.Errorf(apiservices.CodeInvalidArgument, "namespace type %s is not supported", t)
+}
+
+// visibleLeases returns the leases of mgr the peer carried by ctx may inspect, which are
+// the leases on the namespaces it holds and got for the references it is authorized for
+func (svr *namespaceService) visibleLeases(ctx context.Context, t types.NamespaceType, mgr ns.Manager) []types.Lease {
+	leases := mgr.Leases()
+	if auth.Unrestricted(ctx) {
+		return leases
+	}
+	ret := []types.Lease{}
+	for _, l := range leases {
+		refs, err := mgr.Refs(l.NamespaceID)
+		if err != nil {
+			// put back after the leases are listed
+			continue
+		}
+		if svr.authorizeHandle(ctx, nsapi.MethodInspectNamespace, borrowedNamespace{t: t, id: l.NamespaceID}, refs) != nil {
+			continue
+		}
+		ret = append(ret, l)
+	}
+	return ret
+}
+
+// visibleHealth returns the health of the pools of mgr whose references the peer carried by ctx is authorized for
+func visibleHealth(ctx context.Context, mgr ns.Manager) []types.HealthStatus {
+	var ret []types.HealthStatus
+	for _, h := range mgr.Health() {
+		if auth.Authorize(ctx, auth.Request{
+			Service:        cerm.NamespaceService,
+			Method:         nsapi.MethodInspectNamespace,
+			Refs:           []types.Reference{h.Ref},
+			NamespaceTypes: []types.NamespaceType{h.NamespaceType},
+		}) == nil {
+			ret = append(ret, h)
+		}
+	}
+	return ret
 }
@@ -0,0 +1,188 @@
+package namespace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	cerm "github.com/YLonely/cer-manager"
+	apiservices "github.com/YLonely/cer-manager/api/services"
+	nsapi "github.com/YLonely/cer-manager/api/services/namespace"
+	nsv1 "github.com/YLonely/cer-manager/api/services/namespace/v1"
+	"github.com/YLonely/cer-manager/api/types"
+	typesv1 "github.com/YLonely/cer-manager/api/types/v1"
+	"github.com/YLonely/cer-manager/log"
+	"github.com/YLonely/cer-manager/services"
+	"github.com/containerd/ttrpc"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+var _ services.TTRPCService = &namespaceService{}
+
+func (svr *namespaceService) RegisterTTRPC(s *ttrpc.Server) error {
+	nsv1.RegisterNamespaceService(s, &ttrpcService{svr: svr})
+	return nil
+}
+
+// ttrpcService serves the namespace service over ttrpc
+type ttrpcService struct {
+	svr *namespaceService
+}
+
+var _ nsv1.NamespaceService = &ttrpcService{}
+
+// errLeaseRequired is returned to the gets without a lease over ttrpc, the requests are not bound to any connection
+// so the namespaces would never be reclaimed, and the paths handed out are only valid while the lease is held
+var errLeaseRequired = apiservices.Errorf(apiservices.CodeInvalidArgument, "a lease is required to get namespaces over ttrpc")
+
+// expose returns the path of the namespace of key checked out over ttrpc. It points to a duplicate of f
+// which is closed once the namespace is put back, so the path does not lead to the namespace lent to others later
+func (svr *namespaceService) expose(key borrowedNamespace, f *os.File) (string, error) {
+	fd, err := unix.FcntlInt(f.Fd(), unix.F_DUPFD_CLOEXEC, 0)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to duplicate the namespace file")
+	}
+	svr.m.Lock()
+	svr.paths[key] = os.NewFile(uintptr(fd), f.Name())
+	svr.m.Unlock()
+	return fmt.Sprintf("/proc/%d/fd/%d", os.Getpid(), fd), nil
+}
+
+func (s *ttrpcService) Get(ctx context.Context, req *nsv1.GetNamespaceRequest) (*nsv1.GetNamespaceResponse, error) {
+	log.WithInterface(log.Logger(cerm.NamespaceService, "ttrpcGet"), "request", req).Debug()
+	if req.LeaseTTL == 0 && s.svr.leaseTTL == 0 {
+		return &nsv1.GetNamespaceResponse{Error: typesv1.FromError(errLeaseRequired)}, nil
+	}
+	rsp, f := s.svr.getNamespace(ctx, nsapi.GetNamespaceRequest{
+		T:         types.NamespaceType(req.NamespaceType),
+		Ref:       req.Ref.ToReference(),
+		ExtraRefs: typesv1.ToReferences(req.ExtraRefs),
+		LeaseTTL:  time.Duration(req.LeaseTTL),
+		Wait:      time.Duration(req.Wait),
+	})
+	if rsp.Error != nil {
+		return &nsv1.GetNamespaceResponse{Error: typesv1.FromError(rsp.Error)}, nil
+	}
+	info, err := json.Marshal(rsp.Info)
+	var path string
+	if err == nil {
+		path, err = s.svr.expose(borrowedNamespace{t: types.NamespaceType(req.NamespaceType), id: rsp.ID}, f)
+	}
+	if err != nil {
+		s.svr.putNamespace(ctx, nsapi.PutNamespaceRequest{T: types.NamespaceType(req.NamespaceType), ID: rsp.ID})
+		return &nsv1.GetNamespaceResponse{Error: typesv1.FromError(apiservices.ToError(err))}, nil
+	}
+	ret := &nsv1.GetNamespaceResponse{
+		NamespaceID:   rsp.ID,
+		NamespacePath: path,
+		Info:          info,
+	}
+	if rsp.Lease != nil {
+		ret.Lease = nsv1.FromLease(*rsp.Lease)
+	}
+	log.WithInterface(log.Logger(cerm.NamespaceService, "ttrpcGet"), "response", ret).Debug()
+	return ret, nil
+}
+
+func (s *ttrpcService) Put(ctx context.Context, req *nsv1.PutNamespaceRequest) (*nsv1.PutNamespaceResponse, error) {
+	log.WithInterface(log.Logger(cerm.NamespaceService, "ttrpcPut"), "request", req).Debug()
+	rsp := s.svr.putNamespace(ctx, nsapi.PutNamespaceRequest{
+		T:  types.NamespaceType(req.NamespaceType),
+		ID: req.NamespaceID,
+	})
+	return &nsv1.PutNamespaceResponse{Error: typesv1.FromError(rsp.Error)}, nil
+}
+
+func (s *ttrpcService) Update(ctx context.Context, req *nsv1.UpdateNamespaceRequest) (*nsv1.UpdateNamespaceResponse, error) {
+	log.WithInterface(log.Logger(cerm.NamespaceService, "ttrpcUpdate"), "request", req).Debug()
+	rsp := s.svr.updateNamespace(ctx, nsapi.UpdateNamespaceRequest{
+		Ref:      req.Ref.ToReference(),
+		Capacity: int(req.Capacity),
+	})
+	return &nsv1.UpdateNamespaceResponse{Error: typesv1.FromError(rsp.Error)}, nil
+}
+
+func (s *ttrpcService) Renew(ctx context.Context, req *nsv1.RenewNamespaceRequest) (*nsv1.RenewNamespaceResponse, error) {
+	log.WithInterface(log.Logger(cerm.NamespaceService, "ttrpcRenew"), "request", req).Debug()
+	rsp := s.svr.renewNamespace(ctx, nsapi.RenewNamespaceRequest{
+		T:       types.NamespaceType(req.NamespaceType),
+		LeaseID: req.LeaseID,
+		TTL:     time.Duration(req.TTL),
+	})
+	if rsp.Error != nil {
+		return &nsv1.RenewNamespaceResponse{Error: typesv1.FromError(rsp.Error)}, nil
+	}
+	return &nsv1.RenewNamespaceResponse{Lease: nsv1.FromLease(rsp.Lease)}, nil
+}
+
+func (s *ttrpcService) Inspect(ctx context.Context, req *nsv1.InspectNamespaceRequest) (*nsv1.InspectNamespaceResponse, error) {
+	log.WithInterface(log.Logger(cerm.NamespaceService, "ttrpcInspect"), "request", req).Debug()
+	rsp := s.svr.inspectNamespace(ctx, nsapi.InspectNamespaceRequest{
+		T: types.NamespaceType(req.NamespaceType),
+	})
+	if rsp.Error != nil {
+		return &nsv1.InspectNamespaceResponse{Error: typesv1.FromError(rsp.Error)}, nil
+	}
+	status, err := json.Marshal(rsp)
+	if err != nil {
+		return &nsv1.InspectNamespaceResponse{Error: typesv1.FromError(apiservices.ToError(err))}, nil
+	}
+	return &nsv1.InspectNamespaceResponse{Status: status}, nil
+}
+
+func (s *ttrpcService) GetSandbox(ctx context.Context, req *nsv1.GetSandboxRequest) (*nsv1.GetSandboxResponse, error) {
+	log.WithInterface(log.Logger(cerm.NamespaceService, "ttrpcGetSandbox"), "request", req).Debug()
+	if req.LeaseTTL == 0 && s.svr.leaseTTL == 0 {
+		return &nsv1.GetSandboxResponse{Error: typesv1.FromError(errLeaseRequired)}, nil
+	}
+	r := nsapi.GetSandboxRequest{
+		Ref:      req.Ref.ToReference(),
+		LeaseTTL: time.Duration(req.LeaseTTL),
+		Wait:     time.Duration(req.Wait),
+	}
+	for _, t := range req.NamespaceTypes {
+		r.Types = append(r.Types, types.NamespaceType(t))
+	}
+	rsp, files := s.svr.getSandbox(ctx, r)
+	if rsp.Error != nil {
+		return &nsv1.GetSandboxResponse{Error: typesv1.FromError(rsp.Error)}, nil
+	}
+	ret := &nsv1.GetSandboxResponse{
+		SandboxID:      rsp.ID,
+		CheckpointPath: rsp.CheckpointPath,
+	}
+	for i, ns := range rsp.Namespaces {
+		info, err := json.Marshal(ns.Info)
+		var path string
+		if err == nil {
+			path, err = s.svr.expose(borrowedNamespace{t: ns.T, id: ns.ID}, files[i])
+		}
+		if err != nil {
+			s.svr.putSandbox(ctx, nsapi.PutSandboxRequest{ID: rsp.ID})
+			return &nsv1.GetSandboxResponse{Error: typesv1.FromError(apiservices.ToError(err))}, nil
+		}
+		n := &nsv1.SandboxNamespace{
+			NamespaceType: string(ns.T),
+			NamespaceID:   ns.ID,
+			NamespacePath: path,
+			Info:          info,
+		}
+		if ns.Lease != nil {
+			n.Lease = nsv1.FromLease(*ns.Lease)
+		}
+		ret.Namespaces = append(ret.Namespaces, n)
+	}
+	log.WithInterface(log.Logger(cerm.NamespaceService, "ttrpcGetSandbox"), "response", ret).Debug()
+	return ret, nil
+}
+
+func (s *ttrpcService) PutSandbox(ctx context.Context, req *nsv1.PutSandboxRequest) (*nsv1.PutSandboxResponse, error) {
+	log.WithInterface(log.Logger(cerm.NamespaceService, "ttrpcPutSandbox"), "request", req).Debug()
+	rsp := s.svr.putSandbox(ctx, nsapi.PutSandboxRequest{
+		ID: req.SandboxID,
+	})
+	return &nsv1.PutSandboxResponse{Error: typesv1.FromError(rsp.Error)}, nil
+}
@@ -1,13 +1,22 @@
 package services
 
 import (
+	"context"
+	"encoding/json"
 	"net"
+	"sort"
 
+	apiservices "github.com/YLonely/cer-manager/api/services"
 	"github.com/YLonely/cer-manager/utils"
-	"github.com/pkg/errors"
 )
 
-type Handler func(net.Conn) error
+// Handler handles a request on the connection, ctx carries the peer of the connection
+type Handler func(context.Context, net.Conn) error
+
+// errorResponse shares the error field with the responses of all the methods
+type errorResponse struct {
+	Error *apiservices.Error `json:"error"`
+}
 
 type Router struct {
 	hs map[string]Handler
@@ -19,7 +28,7 @@ func NewRouter() Router {
 	}
 }
 
-func (r Router) Handle(c net.Conn) error {
+func (r Router) Handle(ctx context.Context, c net.Conn) error {
 	var method string
 	err := utils.ReceiveObject(c, &method)
 	if err != nil {
@@ -27,11 +36,28 @@ func (r Router) Handle(c net.Conn) error {
 	}
 	handler, exists := r.hs[method]
 	if !exists {
-		return errors.New("no matched hanlder")
+		// drain the request and reply with an error, so the connection can still be used
+		var request json.RawMessage
+		if err = utils.ReceiveObject(c, &request); err != nil {
+			return err
+		}
+		return utils.SendObject(c, errorResponse{
+			Error: apiservices.Errorf(apiservices.CodeUnimplemented, "no matched handler for method %s", method),
+		})
 	}
-	return handler(c)
+	return handler(ctx, c)
 }
 
 func (r *Router) AddHandler(method string, h Handler) {
 	r.hs[method] = h
 }
+
+// Methods returns the names of all the methods handled by the router
+func (r Router) Methods() []string {
+	methods := make([]string, 0, len(r.hs))
+	for method := range r.hs {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return methods
+}
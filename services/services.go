@@ -3,10 +3,39 @@ package services
 import (
 	"context"
 	"net"
+
+	"github.com/containerd/ttrpc"
 )
 
 type Service interface {
 	Init() error
 	Handle(context.Context, net.Conn)
+	// Methods returns the methods supported by the service
+	Methods() []string
 	Stop() error
 }
+
+// TTRPCService is implemented by the services which can also be served over ttrpc
+type TTRPCService interface {
+	RegisterTTRPC(*ttrpc.Server) error
+}
+
+// Reclaimer is implemented by the services which hand out resources to the connections,
+// Reclaim is called after a connection is closed to take back the resources it still holds
+type Reclaimer interface {
+	Reclaim(conn uint64)
+}
+
+type connectionKey struct{}
+
+// WithConnection returns a context carrying the id of a connection on the daemon socket
+func WithConnection(ctx context.Context, conn uint64) context.Context {
+	return context.WithValue(ctx, connectionKey{}, conn)
+}
+
+// ConnectionFromContext returns the id of the connection carried by ctx,
+// the requests over ttrpc are not bound to any connection
+func ConnectionFromContext(ctx context.Context) (uint64, bool) {
+	conn, ok := ctx.Value(connectionKey{}).(uint64)
+	return conn, ok
+}
@@ -52,7 +52,7 @@ func (g *fieldsGatherer) Gather() error {
 			return errors.New("struct does not have field named " + field)
 		}
 		if !f.CanSet() {
-			return errors.Errorf("field %s is unsettable")
+			return errors.Errorf("field %s is unsettable", field)
 		}
 		setValue, err := source()
 		if err != nil {
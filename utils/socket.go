@@ -8,13 +8,17 @@ import (
 	"io"
 	"math"
 	"net"
+	"os"
 
 	cerm "github.com/YLonely/cer-manager"
+	"golang.org/x/sys/unix"
 )
 
 const (
 	dataSizePrefixLen int    = 4
 	dataSizeMax       uint32 = math.MaxUint32
+	// maxFilesPerMessage is the max number of files that can be received along with one object
+	maxFilesPerMessage int = 16
 )
 
 /*
@@ -57,6 +61,15 @@ func SendObject(conn net.Conn, v interface{}) error {
 	return nil
 }
 
+// SendObjectWithFiles sends v to a unix conn, the files are passed as ancillary data(SCM_RIGHTS) along with v
+func SendObjectWithFiles(conn net.Conn, v interface{}, files ...*os.File) error {
+	data, err := WithSizePrefix(v)
+	if err != nil {
+		return err
+	}
+	return SendWithFiles(conn, data, files...)
+}
+
 //WithSizePrefix packs v with prefix of data size
 func WithSizePrefix(v interface{}) ([]byte, error) {
 	dataJSON, err := json.Marshal(v)
@@ -85,6 +98,36 @@ func Send(c net.Conn, data []byte) error {
 	return nil
 }
 
+//SendWithFiles sends data to a unix conn, the fds of files are attached to the first byte of data
+func SendWithFiles(c net.Conn, data []byte, files ...*os.File) error {
+	if len(files) == 0 {
+		return Send(c, data)
+	}
+	if len(files) > maxFilesPerMessage {
+		return errors.New("too many files in one message")
+	}
+	uc, ok := c.(*net.UnixConn)
+	if !ok {
+		return errors.New("files can only be sent over a unix conn")
+	}
+	fds := make([]int, 0, len(files))
+	for _, f := range files {
+		fds = append(fds, int(f.Fd()))
+	}
+	oob := unix.UnixRights(fds...)
+	n, oobn, err := uc.WriteMsgUnix(data, oob, nil)
+	if err != nil {
+		return err
+	}
+	if oobn != len(oob) {
+		return io.ErrShortWrite
+	}
+	if n < len(data) {
+		return Send(c, data[n:])
+	}
+	return nil
+}
+
 //Receive data with size prefix
 func ReceiveObject(c net.Conn, v interface{}) error {
 	var l uint32
@@ -100,6 +143,72 @@ func ReceiveObject(c net.Conn, v interface{}) error {
 	return json.Unmarshal(data, v)
 }
 
+//ReceiveObjectWithFiles receives data with size prefix from a unix conn and the files passed along with it,
+//the caller owns the returned files
+func ReceiveObjectWithFiles(c net.Conn, v interface{}) (files []*os.File, err error) {
+	uc, ok := c.(*net.UnixConn)
+	if !ok {
+		return nil, errors.New("files can only be received from a unix conn")
+	}
+	defer func() {
+		if err != nil {
+			for _, f := range files {
+				f.Close()
+			}
+			files = nil
+		}
+	}()
+	// the ancillary data comes with the first byte of the message, which means it arrives along with the size prefix
+	dataPrefix := make([]byte, dataSizePrefixLen)
+	oob := make([]byte, unix.CmsgSpace(maxFilesPerMessage*4))
+	for read := 0; read < dataSizePrefixLen; {
+		n, oobn, flags, _, err := uc.ReadMsgUnix(dataPrefix[read:], oob)
+		if err != nil {
+			return files, err
+		}
+		if n == 0 {
+			return files, io.EOF
+		}
+		read += n
+		if oobn != 0 {
+			received, err := parseFiles(oob[:oobn])
+			files = append(files, received...)
+			if err != nil {
+				return files, err
+			}
+		}
+		// the kernel drops the files which do not fit in oob, the rest would be paired with the wrong namespaces
+		if flags&unix.MSG_CTRUNC != 0 {
+			return files, errors.New("files passed along with the message are truncated")
+		}
+	}
+	l := binary.BigEndian.Uint32(dataPrefix)
+	data := make([]byte, l)
+	if _, err = io.ReadFull(c, data); err != nil {
+		return files, err
+	}
+	return files, json.Unmarshal(data, v)
+}
+
+func parseFiles(oob []byte) ([]*os.File, error) {
+	msgs, err := unix.ParseSocketControlMessage(oob)
+	if err != nil {
+		return nil, err
+	}
+	var files []*os.File
+	for _, msg := range msgs {
+		fds, err := unix.ParseUnixRights(&msg)
+		if err != nil {
+			return files, err
+		}
+		for _, fd := range fds {
+			unix.CloseOnExec(fd)
+			files = append(files, os.NewFile(uintptr(fd), "fd"))
+		}
+	}
+	return files, nil
+}
+
 func ReceiveServiceType(c net.Conn) (cerm.ServiceType, error) {
 	data := make([]byte, cerm.ServiceTypePrefixLen)
 	if _, err := io.ReadFull(c, data); err != nil {